@@ -1,10 +1,12 @@
 package alligotor
 
 import (
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +17,7 @@ var (
 	ErrStructExpected     = errors.New("expected pointer to struct as input")
 	ErrTypeMismatch       = errors.New("type mismatch when trying to assign")
 	ErrDuplicateConfigKey = errors.New("key already used for a config source")
+	ErrMalformedConfigTag = errors.New("segment without = in config struct tag")
 )
 
 const (
@@ -65,6 +68,20 @@ func Get(v interface{}) error {
 // in the format val1,val2,val3 and string maps (map[string]string) in the format key1=val1,key2=val2.
 type Collector struct {
 	Sources []ConfigSource
+	// resolveRefs enables resolving values like "ref+file:///path", see WithReferences.
+	resolveRefs bool
+}
+
+// CollectorOption takes a Collector as input and modifies it.
+type CollectorOption func(*Collector)
+
+// WithReferences enables resolving values in the format "ref+<scheme>://<ref>" with the registered resolvers
+// (see RegisterResolver). Since everyone that can set a value in one of the sources can then read files and
+// environment variables, references are only resolved if this option is set.
+func WithReferences() CollectorOption {
+	return func(c *Collector) {
+		c.resolveRefs = true
+	}
 }
 
 // New returns a new Collector.
@@ -75,54 +92,142 @@ func New(sources ...ConfigSource) *Collector {
 	return &Collector{Sources: sources}
 }
 
+// WithOptions sets the options for the Collector.
+func (c *Collector) WithOptions(opts ...CollectorOption) *Collector {
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
 // Get is the main package function and can be used by its wrapper Get or on a defined Collector struct.
 // It expects a pointer to the config struct to write the config variables from the configured source to.
 // If the input param is not a pointer to a struct, Get will return an error.
 //
 // Get looks for config variables in all defined sources.
+// If WithReferences is used, values referencing other sources like "ref+file:///path" are resolved before they are set
+// (see RegisterResolver).
+// If any field marked with `config:"required=true"` was not set by any source a RequiredError is returned.
+// Afterwards the resulting values are checked against the rules defined in the validate struct tags.
+// If any rule is violated a ValidationError containing all violations is returned.
 // Further usage details can be found in the examples or the Collector struct's documentation.
 func (c *Collector) Get(v interface{}) error {
-	value := reflect.ValueOf(v)
-	if value.Kind() != reflect.Ptr {
-		return ErrPointerExpected
-	}
+	_, err := c.GetWithReport(v)
+	return err
+}
 
-	t := reflect.Indirect(value)
-	if t.Kind() != reflect.Struct {
-		return ErrStructExpected
+// GetWithReport works just like Get but additionally returns a ProvenanceReport that describes for every field which
+// source set the effective value, under which key it was found and which lower-priority sources were shadowed.
+func (c *Collector) GetWithReport(v interface{}) (*ProvenanceReport, error) {
+	t, err := structValue(v)
+	if err != nil {
+		return nil, err
 	}
 
+	allocateDefaultStructs(t, nil)
+
 	// collect info about fields with tags, value...
 	fields, err := getFieldsConfigsFromValue(t, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, source := range c.Sources {
+	report := newReport(fields)
+
+	// defaults from struct tags are applied before all other sources
+	for _, source := range append([]ConfigSource{&DefaultsSource{}}, c.Sources...) {
 		if initializer, ok := source.(ConfigSourceInitializer); ok {
 			if err := initializer.Init(fields); err != nil {
-				return err
+				return nil, err
 			}
 		}
 
 		for i := range fields {
 			fieldVal, err := source.Read(&fields[i])
 			if err != nil {
-				return err
+				return nil, redactError(&fields[i], err)
 			}
 
-			if err := set(fields[i].value, fieldVal); err != nil {
-				return err
+			resolvedVal := fieldVal
+			if c.resolveRefs {
+				if resolvedVal, err = resolveRef(fieldVal); err != nil {
+					return nil, redactError(&fields[i], fmt.Errorf("%s: %w", fieldPath(&fields[i]), err))
+				}
 			}
+
+			if err := setSeparated(fields[i].value, resolvedVal, fieldSeparators(&fields[i])); err != nil {
+				return nil, redactError(&fields[i], err)
+			}
+
+			report.record(i, source, &fields[i], fieldVal)
 		}
 	}
 
-	return nil
+	if err := checkRequired(fields, report, c.Sources); err != nil {
+		return nil, err
+	}
+
+	if err := validate(fields); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// structValue returns the struct v points to or an error if v is not a pointer to a struct.
+func structValue(v interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr {
+		return reflect.Value{}, ErrPointerExpected
+	}
+
+	t := reflect.Indirect(value)
+	if t.Kind() != reflect.Struct {
+		return reflect.Value{}, ErrStructExpected
+	}
+
+	return t, nil
 }
 
 func getFieldsConfigsFromValue(value reflect.Value, base []Field) ([]Field, error) {
+	structFields, err := collectFields(value, base, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	structFields, err = resolveCollisions(structFields)
+	if err != nil {
+		return nil, err
+	}
+
 	var fields []Field
 
+	for _, structField := range structFields {
+		field := structField.field
+		fields = append(fields, field)
+
+		// value structs like time.Time or Secret are set as a whole instead of by their fields
+		if field.value.Kind() == reflect.Struct && !isValueStruct(field.value) {
+			newBase := append(base, field)
+
+			subFields, err := getFieldsConfigsFromValue(field.value, newBase)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, subFields...)
+		}
+	}
+
+	return fields, nil
+}
+
+// collectFields returns the direct fields of the struct value including the fields of squashed structs.
+// depth is the number of squashed structs the fields are nested in.
+func collectFields(value reflect.Value, base []Field, depth int) ([]structField, error) {
+	var fields []structField
+
 	for i := 0; i < value.NumField(); i++ {
 		fieldType := value.Type().Field(i)
 
@@ -131,30 +236,45 @@ func getFieldsConfigsFromValue(value reflect.Value, base []Field) ([]Field, erro
 			fieldValue = value.Field(i)
 		}
 
-		fieldConfig, err := readParameterConfig(fieldType.Tag.Get(configTagKey))
+		tag := fieldType.Tag.Get(configTagKey)
+		if tag == ignoreValue || isIgnoredKind(fieldValue.Kind()) {
+			continue
+		}
+
+		fieldConfig, err := readParameterConfig(tag)
 		if err != nil {
 			return nil, err
 		}
 
-		field := NewField(
-			base,
-			fieldType.Name,
-			fieldType.Tag.Get(descriptionTagKey),
-			fieldValue,
-			fieldConfig,
-		)
-		fields = append(fields, field)
+		squash, err := shouldSquash(fieldType, fieldValue, fieldConfig)
+		if err != nil {
+			return nil, err
+		}
 
-		if fieldValue.Kind() == reflect.Struct {
-			newBase := append(base, field)
+		if !fieldType.IsExported() && !squash {
+			continue
+		}
 
-			subFields, err := getFieldsConfigsFromValue(fieldValue, newBase)
+		if squash {
+			squashed, err := collectFields(fieldValue, base, depth+1)
 			if err != nil {
 				return nil, err
 			}
 
-			fields = append(fields, subFields...)
+			fields = append(fields, squashed...)
+
+			continue
 		}
+
+		field := NewField(
+			base,
+			fieldType.Name,
+			fieldType.Tag.Get(descriptionTagKey),
+			fieldValue,
+			fieldConfig,
+		)
+		field.validate = fieldType.Tag.Get(validateTagKey)
+		fields = append(fields, structField{field: field, depth: depth})
 	}
 
 	return fields, nil
@@ -167,7 +287,25 @@ func readParameterConfig(configStr string) (map[string]string, error) {
 		return nil, nil
 	}
 
-	for _, paramStr := range strings.Split(configStr, ",") {
+	var params []string
+
+	// segments without "=" belong to the previous value if it's a list, e.g. in sources=file,env
+	for _, paramStr := range splitTag(configStr) {
+		if len(params) > 0 && !strings.Contains(paramStr, "=") {
+			prevKey, _, _ := strings.Cut(params[len(params)-1], "=")
+			if !isListKey(prevKey) {
+				return nil, fmt.Errorf("%s: %w", paramStr, ErrMalformedConfigTag)
+			}
+
+			params[len(params)-1] += "," + paramStr
+
+			continue
+		}
+
+		params = append(params, paramStr)
+	}
+
+	for _, paramStr := range params {
 		keyVal := strings.SplitN(paramStr, "=", 2)
 		if len(keyVal) != 2 {
 			panic("invalid config struct tag format")
@@ -192,7 +330,41 @@ func readParameterConfig(configStr string) (map[string]string, error) {
 	return fieldConfig, nil
 }
 
+// isListKey checks if the value of the config struct tag key can contain commas.
+func isListKey(key string) bool {
+	return key == sourcesKey || key == defaultKey
+}
+
+// splitTag splits the segments of a config or validate struct tag by ",". Commas that are escaped with a backslash
+// like in `validate:"regex=^\\d{1\\,3}$"` are kept as part of the segment.
+func splitTag(tag string) []string {
+	var (
+		segments []string
+		current  strings.Builder
+	)
+
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			segments = append(segments, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+
+	return append(segments, current.String())
+}
+
 func set(target reflect.Value, value interface{}) error {
+	return setSeparated(target, value, separators{})
+}
+
+// setSeparated works like set but uses the given separators to split slices and maps read from strings.
+func setSeparated(target reflect.Value, value interface{}, seps separators) error {
 	if value == nil {
 		return nil
 	}
@@ -204,7 +376,7 @@ func set(target reflect.Value, value interface{}) error {
 
 		var err error
 
-		value, err = fromString(target, string(bytes))
+		value, err = parseString(target, string(bytes), seps)
 		if err != nil {
 			return err
 		}
@@ -214,6 +386,15 @@ func set(target reflect.Value, value interface{}) error {
 }
 
 func fromString(target reflect.Value, value string) (interface{}, error) {
+	return parseString(target, value, separators{})
+}
+
+// parseString parses the value into the target's type using the given separators for slices and maps.
+func parseString(target reflect.Value, value string, seps separators) (interface{}, error) {
+	if parsed, ok, err := parseCollection(target, value, seps); ok {
+		return parsed, err
+	}
+
 	specialVal, err := specialTypes(target, value)
 	if err != nil {
 		return nil, err
@@ -283,6 +464,78 @@ func specialTypes(target reflect.Value, value string) (finalVal interface{}, err
 	return nil, nil
 }
 
+// toString is the inverse of fromString and formats a value so that it can be parsed by fromString again.
+// Nil pointers result in an empty string.
+func toString(value reflect.Value) string {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || !value.CanInterface() {
+		return ""
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(textMarshaler) {
+		value = value.Addr()
+	}
+
+	switch typedVal := value.Interface().(type) {
+	case time.Duration:
+		return typedVal.String()
+	case time.Time:
+		return typedVal.Format(time.RFC3339)
+	case encoding.TextMarshaler:
+		if text, err := typedVal.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	value = reflect.Indirect(value)
+
+	//nolint:exhaustive // all other kinds are marshaled as json
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 || !isSplittable(value.Type().Elem()) {
+			break
+		}
+
+		elems := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elems = append(elems, toString(value.Index(i)))
+		}
+
+		return strings.Join(elems, ",")
+	case reflect.Map:
+		if !isSplittable(value.Type().Key()) || !isSplittable(value.Type().Elem()) {
+			break
+		}
+
+		keyVals := make([]string, 0, value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			keyVals = append(keyVals, toString(iter.Key())+"="+toString(iter.Value()))
+		}
+
+		sort.Strings(keyVals)
+
+		return strings.Join(keyVals, ",")
+	}
+
+	jsonBytes, err := json.Marshal(value.Interface())
+	if err != nil {
+		return fmt.Sprint(value.Interface())
+	}
+
+	return string(jsonBytes)
+}
+
 func trySet(target, value reflect.Value) (err error) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -114,6 +114,26 @@ var _ = Describe("config", func() {
 			Expect(val).To(Equal(expected))
 		})
 	})
+	Describe("toString", func() {
+		It("formats values so that they can be parsed again", func() {
+			values := []interface{}{
+				"test", 420, true, 2 * time.Hour,
+				time.Date(2007, 01, 02, 15, 04, 05, 00, time.UTC),
+				[]string{"a", "b"}, map[string]string{"b": "b", "a": "a"},
+				[]int{80, 443}, []time.Duration{time.Second}, map[string]int{"a": 1, "b": 2},
+			}
+
+			for _, value := range values {
+				target := reflect.New(reflect.TypeOf(value)).Elem()
+				parsed, err := fromString(target, toString(reflect.ValueOf(value)))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed).To(Equal(value))
+			}
+		})
+		It("returns empty string for nil pointers", func() {
+			Expect(toString(reflect.ValueOf((*int)(nil)))).To(Equal(""))
+		})
+	})
 	Describe("set", func() {
 		var target *struct{ V int }
 		BeforeEach(func() {
@@ -362,8 +382,8 @@ type testingConfigPointers struct {
 }
 
 type testingConfigEmbedded struct {
-	test.APIConfig
-	test.DBConfig
+	test.APIConfig `config:"squash=false"`
+	test.DBConfig  `config:"squash=false"`
 }
 
 func wrappedValue(val interface{}) reflect.Value {
@@ -0,0 +1,165 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	sepKey       = "sep"
+	kvSepKey     = "kvsep"
+	defaultSep   = ","
+	defaultKVSep = "="
+)
+
+var ErrMalformedMapEntry = errors.New("malformed map entry")
+
+// separators define how slices and maps are split when they are parsed from a string.
+// Empty values mean that the defaults "," and "=" are used.
+type separators struct {
+	elem   string
+	keyVal string
+}
+
+// fieldSeparators returns the separators that are configured for the field with `config:"sep=;,kvsep=:"`.
+func fieldSeparators(f *Field) separators {
+	return separators{elem: f.Configs()[sepKey], keyVal: f.Configs()[kvSepKey]}
+}
+
+func (s separators) withDefaults() separators {
+	if s.elem == "" {
+		s.elem = defaultSep
+	}
+
+	if s.keyVal == "" {
+		s.keyVal = defaultKVSep
+	}
+
+	return s
+}
+
+// parseCollection parses slices like "80,443" and maps like "a=1,b=2" whose elements can be parsed from a string.
+// If the target is no such slice or map, ok is false. Values starting with "[" or "{" are left to be parsed as JSON,
+// except for string elements.
+func parseCollection(target reflect.Value, value string, seps separators) (parsed interface{}, ok bool, err error) {
+	if implementsTextUnmarshaler(target.Type()) {
+		return nil, false, nil
+	}
+
+	seps = seps.withDefaults()
+	t := target.Type()
+	trimmed := strings.TrimSpace(value)
+
+	//nolint:exhaustive // only slices and maps are collections
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 || !isSplittable(t.Elem()) ||
+			(t.Elem().Kind() != reflect.String && strings.HasPrefix(trimmed, "[")) {
+			return nil, false, nil
+		}
+
+		parsed, err := parseSlice(t, value, seps)
+
+		return parsed, true, err
+	case reflect.Map:
+		if !isSplittable(t.Key()) || !isSplittable(t.Elem()) ||
+			((t.Key().Kind() != reflect.String || t.Elem().Kind() != reflect.String) && strings.HasPrefix(trimmed, "{")) {
+			return nil, false, nil
+		}
+
+		parsed, err := parseMap(t, value, seps)
+
+		return parsed, true, err
+	}
+
+	return nil, false, nil
+}
+
+func parseSlice(t reflect.Type, value string, seps separators) (interface{}, error) {
+	parts := strings.Split(value, seps.elem)
+	if value == "" && t.Elem().Kind() != reflect.String {
+		parts = nil
+	}
+
+	slice := reflect.MakeSlice(t, 0, len(parts))
+
+	for _, part := range parts {
+		elem, err := parseElem(t.Elem(), strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+
+		slice = reflect.Append(slice, elem)
+	}
+
+	return slice.Interface(), nil
+}
+
+func parseMap(t reflect.Type, value string, seps separators) (interface{}, error) {
+	m := reflect.MakeMap(t)
+	if strings.TrimSpace(value) == "" {
+		return m.Interface(), nil
+	}
+
+	for _, entry := range strings.Split(value, seps.elem) {
+		keyVal := strings.SplitN(entry, seps.keyVal, 2)
+		if len(keyVal) != 2 {
+			return nil, fmt.Errorf("%w: expected the format key%svalue", ErrMalformedMapEntry, seps.keyVal)
+		}
+
+		key, err := parseElem(t.Key(), strings.TrimSpace(keyVal[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := parseElem(t.Elem(), strings.TrimSpace(keyVal[1]))
+		if err != nil {
+			return nil, err
+		}
+
+		m.SetMapIndex(key, val)
+	}
+
+	return m.Interface(), nil
+}
+
+// parseElem parses a single element of a slice or map.
+func parseElem(t reflect.Type, value string) (reflect.Value, error) {
+	elem := reflect.New(t).Elem()
+
+	parsed, err := fromString(elem, value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if err := trySet(elem, reflect.ValueOf(parsed)); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return elem, nil
+}
+
+// isSplittable checks if values of the type can be elements of slices or maps that are parsed from a string,
+// i.e. they don't contain separators themselves like structs or slices.
+func isSplittable(t reflect.Type) bool {
+	if implementsTextUnmarshaler(t) || t == durationType || t == timeType {
+		return true
+	}
+
+	//nolint:exhaustive // only basic kinds can be split
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+// implementsTextUnmarshaler checks if the type or a pointer to it implements encoding.TextUnmarshaler.
+func implementsTextUnmarshaler(t reflect.Type) bool {
+	return t.Implements(textUnmarshaler) || reflect.PtrTo(t).Implements(textUnmarshaler)
+}
@@ -0,0 +1,84 @@
+package alligotor
+
+import (
+	"net"
+	"os"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("collections", func() {
+	parse := func(target interface{}, value string, seps separators) (interface{}, error) {
+		return parseString(reflect.New(reflect.TypeOf(target)).Elem(), value, seps)
+	}
+
+	Describe("parseString", func() {
+		It("parses typed slices", func() {
+			Expect(parse([]int{}, "80, 443", separators{})).To(Equal([]int{80, 443}))
+			Expect(parse([]float64{}, "1.5,2", separators{})).To(Equal([]float64{1.5, 2}))
+			Expect(parse([]bool{}, "true,false", separators{})).To(Equal([]bool{true, false}))
+			Expect(parse([]time.Duration{}, "1s,2m", separators{})).To(Equal([]time.Duration{time.Second, 2 * time.Minute}))
+			Expect(parse([]net.IP{}, "127.0.0.1,::1", separators{})).To(Equal([]net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}))
+		})
+		It("parses typed maps", func() {
+			Expect(parse(map[string]int{}, "a=1, b=2", separators{})).To(Equal(map[string]int{"a": 1, "b": 2}))
+			Expect(parse(map[string]time.Duration{}, "read=1s", separators{})).
+				To(Equal(map[string]time.Duration{"read": time.Second}))
+		})
+		It("uses the given separators", func() {
+			Expect(parse([]string{}, "a,b;c", separators{elem: ";"})).To(Equal([]string{"a,b", "c"}))
+			Expect(parse(map[string]string{}, "a:1,2;b:3", separators{elem: ";", keyVal: ":"})).
+				To(Equal(map[string]string{"a": "1,2", "b": "3"}))
+		})
+		It("returns empty collections for empty values", func() {
+			Expect(parse([]int{}, "", separators{})).To(Equal([]int{}))
+			Expect(parse(map[string]int{}, "", separators{})).To(Equal(map[string]int{}))
+		})
+		It("still supports json", func() {
+			Expect(parse([]int{}, "[1,2]", separators{})).To(Equal([]int{1, 2}))
+			Expect(parse(map[string]int{}, `{"a":1}`, separators{})).To(Equal(map[string]int{"a": 1}))
+		})
+		It("returns errors for invalid elements", func() {
+			_, err := parse([]int{}, "1,a", separators{})
+			Expect(err).To(HaveOccurred())
+
+			_, err = parse(map[string]int{}, "a", separators{})
+			Expect(err).To(MatchError(ErrMalformedMapEntry))
+		})
+	})
+	Describe("Collector", func() {
+		type testConfig struct {
+			Ports    []int
+			Timeouts map[string]time.Duration
+			Hosts    []string          `config:"sep=;"`
+			Labels   map[string]string `config:"sep=;,kvsep=:"`
+		}
+
+		It("reads typed slices and maps from env and flags", func() {
+			for name, val := range map[string]string{
+				"APP_PORTS":    "80,443",
+				"APP_TIMEOUTS": "read=1s,write=2s",
+				"APP_LABELS":   "a:1,2;b:3",
+			} {
+				Expect(os.Setenv(name, val)).To(Succeed())
+				DeferCleanup(os.Unsetenv, name)
+			}
+
+			args := os.Args
+			DeferCleanup(func() { os.Args = args })
+			os.Args = []string{"cmd", "--hosts", "a,b;c"}
+
+			cfg := testConfig{}
+			Expect(New(NewEnvSource("app"), NewFlagsSource()).Get(&cfg)).To(Succeed())
+			Expect(cfg).To(Equal(testConfig{
+				Ports:    []int{80, 443},
+				Timeouts: map[string]time.Duration{"read": time.Second, "write": 2 * time.Second},
+				Hosts:    []string{"a,b", "c"},
+				Labels:   map[string]string{"a": "1,2", "b": "3"},
+			}))
+		})
+	})
+})
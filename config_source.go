@@ -1,6 +1,7 @@
 package alligotor
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -17,6 +18,8 @@ type Field struct {
 	// configs contains structtag key -> value string and can be read to interpret the field's struct tags for
 	// custom behavior like overrides.
 	configs map[string]string
+	// validate contains the value of the validate struct tag with the rules the field's value is checked against.
+	validate string
 }
 
 func NewField(base []Field, name, description string, value reflect.Value, configs map[string]string) Field {
@@ -81,3 +84,20 @@ type ConfigSourceInitializer interface {
 	// could be altered in the time between constructing a config source and calling the Read method.
 	Init(fields []Field) error
 }
+
+// ConfigSourceLocator is an optional interface to implement and can be used to describe where a config source looks
+// for the value of a certain field, for example the name of an environment variable or the key and path of a file.
+// It is used to fill the Key of an Origin in the ProvenanceReport returned by Collector.GetWithReport.
+type ConfigSourceLocator interface {
+	// Locate should return the key under which the source looks for the field's value.
+	// It is called after Read for the same field.
+	Locate(field *Field) string
+}
+
+// ConfigSourceWatcher is an optional interface to implement and can be used to signal that the config source's
+// underlying data changed. It is used by Collector.Watch to trigger a reload of the config.
+type ConfigSourceWatcher interface {
+	// Watch should send to the changed channel whenever the data might have changed until ctx is done.
+	// Sending must not block, since the Collector only needs to know that anything changed.
+	Watch(ctx context.Context, changed chan<- struct{}) error
+}
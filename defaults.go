@@ -0,0 +1,145 @@
+package alligotor
+
+import (
+	"reflect"
+)
+
+const defaultKey = "default"
+
+// DefaultsSource reads the default values defined with `config:"default=..."` struct tags.
+// Collector.Get applies it automatically before all other sources, so defaults have the lowest priority.
+// The values are parsed just like values from environment variables, e.g. `config:"default=5s"` for a time.Duration
+// or `config:"default=80,443"` for a []int. Since "=" also separates the tag's keys and values, commas in defaults
+// for maps need to be escaped like in `config:"default=a=1\\,b=2"`.
+type DefaultsSource struct{}
+
+// Read returns the default value for a certain field or nil if it has none.
+func (s *DefaultsSource) Read(field *Field) (interface{}, error) {
+	def, ok := field.Configs()[defaultKey]
+	if !ok {
+		return nil, nil
+	}
+
+	return []byte(def), nil
+}
+
+// copyWithDefaultStructs returns a copy of the struct in which nil pointers to structs with defaults are allocated.
+// Pointers to nested structs are copied as well, so that the given value is never modified.
+func copyWithDefaultStructs(value reflect.Value) reflect.Value {
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+
+	copyNestedStructs(copied, nil)
+	allocateDefaultStructs(copied, nil)
+
+	return copied
+}
+
+// copyNestedStructs replaces all pointers to structs by pointers to copies of the structs.
+// path contains the struct types that are currently copied. Pointers to these types are not followed,
+// just like in allocateDefaultStructs.
+func copyNestedStructs(value reflect.Value, path map[reflect.Type]bool) {
+	path = enterStruct(path, value.Type())
+	defer delete(path, value.Type())
+
+	for i := 0; i < value.NumField(); i++ {
+		fieldValue := value.Field(i)
+		if !fieldValue.CanSet() || isRecursivePointer(fieldValue, path) {
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() && fieldValue.Elem().Kind() == reflect.Struct {
+			copied := reflect.New(fieldValue.Type().Elem())
+			copied.Elem().Set(fieldValue.Elem())
+			fieldValue.Set(copied)
+		}
+
+		fieldValue = reflect.Indirect(fieldValue)
+		if fieldValue.Kind() == reflect.Struct && !isValueStruct(fieldValue) {
+			copyNestedStructs(fieldValue, path)
+		}
+	}
+}
+
+// allocateDefaultStructs allocates all nil pointers to structs that contain fields with default values,
+// so that the defaults can be applied to their fields.
+// path contains the struct types that are currently allocated. Pointers to these types are not allocated since
+// recursive types like linked lists would be allocated endlessly otherwise.
+func allocateDefaultStructs(value reflect.Value, path map[reflect.Type]bool) {
+	path = enterStruct(path, value.Type())
+	defer delete(path, value.Type())
+
+	for i := 0; i < value.NumField(); i++ {
+		fieldValue := value.Field(i)
+		if !fieldValue.CanSet() || value.Type().Field(i).Tag.Get(configTagKey) == ignoreValue ||
+			isRecursivePointer(fieldValue, path) {
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() && hasDefaults(fieldValue.Type().Elem(), nil) {
+			fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+		}
+
+		fieldValue = reflect.Indirect(fieldValue)
+		if fieldValue.Kind() == reflect.Struct && !isValueStruct(fieldValue) {
+			allocateDefaultStructs(fieldValue, path)
+		}
+	}
+}
+
+// enterStruct adds t to the path of struct types, which is created if it's nil.
+func enterStruct(path map[reflect.Type]bool, t reflect.Type) map[reflect.Type]bool {
+	if path == nil {
+		path = map[reflect.Type]bool{}
+	}
+
+	path[t] = true
+
+	return path
+}
+
+// isRecursivePointer checks if the value is a pointer to a struct type that is already on the path.
+func isRecursivePointer(value reflect.Value, path map[reflect.Type]bool) bool {
+	return value.Kind() == reflect.Ptr && path[value.Type().Elem()]
+}
+
+// hasDefaults checks if t is a struct that contains fields with default values, also in nested structs.
+// visited contains the types that are already checked to support recursive types.
+func hasDefaults(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+
+	if visited == nil {
+		visited = map[reflect.Type]bool{}
+	}
+
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+
+		tag := fieldType.Tag.Get(configTagKey)
+		if tag == ignoreValue {
+			continue
+		}
+
+		configs, err := readParameterConfig(tag)
+		if err == nil {
+			if _, ok := configs[defaultKey]; ok {
+				return true
+			}
+		}
+
+		nested := fieldType.Type
+		if nested.Kind() == reflect.Ptr {
+			nested = nested.Elem()
+		}
+
+		if hasDefaults(nested, visited) {
+			return true
+		}
+	}
+
+	return false
+}
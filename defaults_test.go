@@ -0,0 +1,108 @@
+package alligotor
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+)
+
+var _ = Describe("defaults", func() {
+	type tls struct {
+		Enabled bool `config:"default=true"`
+	}
+
+	type testConfig struct {
+		Timeout  time.Duration  `config:"default=5s"`
+		Ports    []int          `config:"default=80,443"`
+		Retries  int            `config:"default=3"`
+		Password Secret[string] `config:"default=changeme"`
+		Name     string
+		TLS      *tls
+	}
+
+	It("applies defaults from struct tags with the lowest priority", func() {
+		Expect(os.Setenv("APP_RETRIES", "5")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "APP_RETRIES")
+
+		cfg := testConfig{Name: "preset"}
+		report, err := New(
+			NewReadersSource(bytes.NewReader([]byte(`{"timeout": "10s"}`))),
+			NewEnvSource("app"),
+		).GetWithReport(&cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cfg.Timeout).To(Equal(10 * time.Second))
+		Expect(cfg.Ports).To(Equal([]int{80, 443}))
+		Expect(cfg.Retries).To(Equal(5))
+		Expect(cfg.Password.Value()).To(Equal("changeme"))
+		Expect(cfg.Name).To(Equal("preset"))
+
+		fieldReport, ok := report.Field("Ports")
+		Expect(ok).To(BeTrue())
+		Expect(fieldReport.Origin.Source).To(BeAssignableToTypeOf(&DefaultsSource{}))
+	})
+	It("allocates nil pointers to structs with defaults", func() {
+		cfg := testConfig{}
+		Expect(New().Get(&cfg)).To(Succeed())
+		Expect(cfg.TLS).To(Equal(&tls{Enabled: true}))
+	})
+	It("supports escaped commas in defaults for maps", func() {
+		cfg := struct {
+			Labels map[string]string `config:"default=a=1\\,b=2,env=LABELS"`
+		}{}
+
+		Expect(New().Get(&cfg)).To(Succeed())
+		Expect(cfg.Labels).To(Equal(map[string]string{"a": "1", "b": "2"}))
+	})
+	It("supports recursive types", func() {
+		type node struct {
+			V    int `config:"default=1"`
+			Next *node
+		}
+
+		cfg := struct {
+			Root node
+		}{}
+
+		Expect(New().Get(&cfg)).To(Succeed())
+		Expect(cfg.Root.V).To(Equal(1))
+		Expect(cfg.Root.Next).To(BeNil())
+
+		_, err := New(NewFlagsSource()).Docs(&cfg, DocsMarkdown)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(NewFlagsSource(WithFlagSet(pflag.NewFlagSet("test", pflag.ContinueOnError))).RegisterFlags(&cfg)).
+			To(Succeed())
+	})
+	It("returns an error for invalid defaults", func() {
+		cfg := struct {
+			Timeout time.Duration `config:"default=abc"`
+		}{}
+		Expect(New().Get(&cfg)).ToNot(Succeed())
+	})
+	It("shows defaults in the docs", func() {
+		docs, err := New(NewFlagsSource()).Docs(&testConfig{}, DocsMarkdown)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(docs)).To(ContainSubstring("`5s`"))
+		Expect(string(docs)).To(ContainSubstring("`TLS.Enabled`"))
+		Expect(string(docs)).ToNot(ContainSubstring("changeme"))
+	})
+	It("does not modify the struct when generating docs or registering flags", func() {
+		type nested struct {
+			TLS *tls
+		}
+
+		cfg := struct {
+			Nested *nested
+		}{Nested: &nested{}}
+
+		_, err := New(NewFlagsSource()).Docs(&cfg, DocsMarkdown)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(NewFlagsSource(WithFlagSet(pflag.NewFlagSet("test", pflag.ContinueOnError))).RegisterFlags(&cfg)).
+			To(Succeed())
+		Expect(cfg.Nested.TLS).To(BeNil())
+	})
+})
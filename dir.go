@@ -0,0 +1,178 @@
+package alligotor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// DirSource is used to read the configuration from a directory tree where each file name is a key and its content
+// is the value. Nested directories map to nested structs, so for example the file "db/password" sets the field
+// DB.Password. Names are matched case-insensitively and can be overwritten with `config:"file=..."` just like
+// for the FilesSource.
+//
+// This is the layout used by Kubernetes for mounted ConfigMaps and Secrets as well as by systemd for
+// $CREDENTIALS_DIRECTORY. Entries starting with ".." like the "..data" directory created by Kubernetes are skipped
+// and trailing newlines of the files' content are removed.
+type DirSource struct {
+	fsys fs.FS
+	root string
+	// name is the directory's path used to describe where a value was read from
+	name string
+	// pollInterval is the interval in which the directory is checked for changes in Watch
+	pollInterval time.Duration
+	values       *ciMap
+}
+
+// NewDirSource returns a new DirSource that reads the directory at the given path on the local filesystem.
+func NewDirSource(dir string) *DirSource {
+	s := NewFSDirSource(os.DirFS(dir), ".")
+	s.name = dir
+
+	return s
+}
+
+// NewFSDirSource returns a new DirSource that reads the directory at the given path in fsys.
+func NewFSDirSource(fsys fs.FS, dir string) *DirSource {
+	return &DirSource{
+		fsys:         fsys,
+		root:         dir,
+		name:         dir,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// WithPollInterval sets the interval in which the directory is checked for changes when used in Collector.Watch.
+// It returns the DirSource to be able to use it inline when defining a Collector's sources.
+func (s *DirSource) WithPollInterval(interval time.Duration) *DirSource {
+	s.pollInterval = interval
+	return s
+}
+
+// Init reads the whole directory tree.
+// It should be used right before calling the Read method to load the latest state.
+// If the directory doesn't exist, no values are read.
+func (s *DirSource) Init(_ []Field) error {
+	values := map[string]interface{}{}
+
+	err := s.walk(s.root, func(filePath string, nested []string) error {
+		content, err := fs.ReadFile(s.fsys, filePath)
+		if err != nil {
+			return err
+		}
+
+		m := values
+		for _, dir := range nested[:len(nested)-1] {
+			child, ok := m[dir].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				m[dir] = child
+			}
+
+			m = child
+		}
+
+		m[nested[len(nested)-1]] = strings.TrimRight(string(content), "\r\n")
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.values = &ciMap{m: values}
+
+	return nil
+}
+
+// Read returns the content of the file for a certain field.
+// If the file doesn't exist it returns nil.
+func (s *DirSource) Read(field *Field) (interface{}, error) {
+	if s.values == nil {
+		return nil, nil
+	}
+
+	return readFileMap(field, s.values)
+}
+
+// Locate returns the path of the file that is read for a certain field.
+func (s *DirSource) Locate(field *Field) string {
+	if field.IgnoredBy(fileKey) {
+		return ""
+	}
+
+	return path.Join(append([]string{s.name}, append(field.BaseNames(extractFileName), extractFileName(field))...)...)
+}
+
+// Watch polls the directory and notifies the changed channel if files are added, removed or modified.
+func (s *DirSource) Watch(ctx context.Context, changed chan<- struct{}) error {
+	poll(ctx, s.pollInterval, changed, s.dirState)
+	return nil
+}
+
+// dirState returns a string describing the current state of all files in the directory.
+func (s *DirSource) dirState() string {
+	var state strings.Builder
+
+	err := s.walk(s.root, func(filePath string, _ []string) error {
+		info, err := fs.Stat(s.fsys, filePath)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(&state, "%s:%d:%d\n", filePath, info.Size(), info.ModTime().UnixNano())
+
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(&state, "%s\n", err)
+	}
+
+	return state.String()
+}
+
+// walk calls fn for every file in the directory tree with its path in the fs and its names relative to the root.
+// In contrast to fs.WalkDir symlinks are followed, since mounted volumes in Kubernetes consist of symlinks.
+func (s *DirSource) walk(dir string, fn func(filePath string, nested []string) error, nested ...string) error {
+	entries, err := fs.ReadDir(s.fsys, dir)
+	if err != nil {
+		if len(nested) == 0 && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+
+		entryPath := path.Join(dir, entry.Name())
+		entryNested := append(append([]string(nil), nested...), entry.Name())
+
+		info, err := fs.Stat(s.fsys, entryPath)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if err := s.walk(entryPath, fn, entryNested...); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := fn(entryPath, entryNested); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
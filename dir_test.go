@@ -0,0 +1,73 @@
+package alligotor
+
+import (
+	"os"
+	"path"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("dir", func() {
+	type testConfig struct {
+		Port int
+		DB   struct {
+			Password string
+			User     string `config:"file=username"`
+		}
+		Labels map[string]string
+	}
+
+	Describe("NewFSDirSource", func() {
+		var s *DirSource
+		BeforeEach(func() {
+			s = NewFSDirSource(fstest.MapFS(map[string]*fstest.MapFile{
+				"etc/app/port":           {Data: []byte("8080\n")},
+				"etc/app/db/password":    {Data: []byte("secret\n")},
+				"etc/app/db/username":    {Data: []byte("user")},
+				"etc/app/labels/team":    {Data: []byte("core")},
+				"etc/app/..data/ignored": {Data: []byte("ignored")},
+			}), "etc/app")
+		})
+		It("reads files as keys and directories as nested structs", func() {
+			var cfg testConfig
+			Expect(New(s).Get(&cfg)).To(Succeed())
+			Expect(cfg.Port).To(Equal(8080))
+			Expect(cfg.DB.Password).To(Equal("secret"))
+			Expect(cfg.DB.User).To(Equal("user"))
+			Expect(cfg.Labels).To(Equal(map[string]string{"team": "core"}))
+		})
+		It("skips entries starting with ..", func() {
+			Expect(s.Init(nil)).To(Succeed())
+			_, ok := s.values.Get(nil, "..data")
+			Expect(ok).To(BeFalse())
+		})
+		It("returns the file path in Locate", func() {
+			field := &Field{name: "User", base: []Field{{name: "DB"}}, configs: map[string]string{fileKey: "username"}}
+			Expect(s.Locate(field)).To(Equal("etc/app/DB/username"))
+		})
+		It("reads nothing if the directory does not exist", func() {
+			var cfg testConfig
+			Expect(New(NewFSDirSource(fstest.MapFS{}, "missing")).Get(&cfg)).To(Succeed())
+			Expect(cfg).To(Equal(testConfig{}))
+		})
+	})
+	Describe("NewDirSource", func() {
+		It("follows symlinks like in mounted Kubernetes volumes", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.MkdirAll(path.Join(dir, "..2024_01_01", "db"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(path.Join(dir, "..2024_01_01", "port"), []byte("1234\n"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(path.Join(dir, "..2024_01_01", "db", "password"), []byte("pw"), os.ModePerm)).
+				To(Succeed())
+			Expect(os.Symlink("..2024_01_01", path.Join(dir, "..data"))).To(Succeed())
+			Expect(os.Symlink("..data/port", path.Join(dir, "port"))).To(Succeed())
+			Expect(os.Symlink("..data/db", path.Join(dir, "db"))).To(Succeed())
+
+			var cfg testConfig
+			Expect(New(NewDirSource(dir)).Get(&cfg)).To(Succeed())
+			Expect(cfg.Port).To(Equal(1234))
+			Expect(cfg.DB.Password).To(Equal("pw"))
+		})
+	})
+})
@@ -0,0 +1,190 @@
+package alligotor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DocsFormat defines the format that is used by Collector.Docs.
+type DocsFormat string
+
+const (
+	// DocsMarkdown generates a Markdown table.
+	DocsMarkdown DocsFormat = "markdown"
+	// DocsMan generates a man page section in roff format.
+	DocsMan DocsFormat = "man"
+)
+
+var ErrUnknownDocsFormat = errors.New("unknown docs format")
+
+// fieldDoc contains all information that is documented for a single field.
+type fieldDoc struct {
+	path         string
+	typ          string
+	defaultValue string
+	description  string
+	env          string
+	flag         string
+	fileKey      string
+}
+
+// Docs generates reference documentation for the given config struct.
+// It lists every field's path, Go type, default value (the value preset in v or the default tag), description and
+// the keys that can be used to set it in the Collector's sources: the env var name for the first EnvSource, the flag
+// names for the first FlagsSource and the file key. The env and flag columns are left out if the Collector has no
+// such source.
+func (c *Collector) Docs(v interface{}, format DocsFormat) ([]byte, error) {
+	value, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// document the fields of nil pointers to structs with defaults without modifying v
+	fields, err := getFieldsConfigsFromValue(copyWithDefaultStructs(value), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := c.fieldDocs(leafFields(fields))
+
+	switch format {
+	case DocsMarkdown:
+		return markdownDocs(docs, c.findEnvSource() != nil, c.findFlagsSource() != nil), nil
+	case DocsMan:
+		return manDocs(docs), nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", format, ErrUnknownDocsFormat)
+}
+
+func (c *Collector) fieldDocs(leaves []*Field) []fieldDoc {
+	env := c.findEnvSource()
+	flags := c.findFlagsSource()
+	docs := make([]fieldDoc, 0, len(leaves))
+
+	for _, f := range leaves {
+		doc := fieldDoc{
+			path:         fieldPath(f),
+			typ:          displayType(f.Type()).String(),
+			defaultValue: defaultValue(f),
+			description:  f.Description(),
+		}
+
+		if !f.IgnoredBy(fileKey) {
+			doc.fileKey = strings.Join(append(f.BaseNames(extractFileName), extractFileName(f)), ".")
+		}
+
+		if env != nil {
+			doc.env = env.Locate(f)
+		}
+
+		if flags != nil && !f.IgnoredBy(flagKey) {
+			doc.flag = flags.Locate(f)
+
+			// ignore error since malformed flag configs are reported by the FlagsSource itself
+			if flagConfig, _ := readFlagConfig(f.Configs()[flagKey]); flagConfig.ShortName != "" {
+				doc.flag += ", -" + flagConfig.ShortName
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs
+}
+
+func markdownDocs(docs []fieldDoc, withEnv, withFlags bool) []byte {
+	var buf bytes.Buffer
+
+	header := []string{"Field", "Type", "Default", "Description"}
+	if withEnv {
+		header = append(header, "Env")
+	}
+
+	if withFlags {
+		header = append(header, "Flag")
+	}
+
+	header = append(header, "File key")
+
+	writeMarkdownRow(&buf, header)
+	writeMarkdownRow(&buf, strings.Split(strings.Repeat("---,", len(header)-1)+"---", ","))
+
+	for _, doc := range docs {
+		row := []string{code(doc.path), code(doc.typ), code(doc.defaultValue), doc.description}
+		if withEnv {
+			row = append(row, code(doc.env))
+		}
+
+		if withFlags {
+			row = append(row, code(doc.flag))
+		}
+
+		row = append(row, code(doc.fileKey))
+
+		writeMarkdownRow(&buf, row)
+	}
+
+	return buf.Bytes()
+}
+
+func writeMarkdownRow(buf *bytes.Buffer, cells []string) {
+	for i := range cells {
+		cells[i] = strings.ReplaceAll(cells[i], "|", `\|`)
+	}
+
+	_, _ = fmt.Fprintf(buf, "| %s |\n", strings.Join(cells, " | "))
+}
+
+// code wraps a non-empty string in backticks.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + s + "`"
+}
+
+func manDocs(docs []fieldDoc) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(".SH CONFIGURATION\n")
+
+	for _, doc := range docs {
+		_, _ = fmt.Fprintf(&buf, ".TP\n.B %s\n", roffEscape(doc.path))
+
+		if doc.description != "" {
+			_, _ = fmt.Fprintf(&buf, "%s\n.br\n", roffEscape(doc.description))
+		}
+
+		_, _ = fmt.Fprintf(&buf, "Type: %s\n", roffEscape(doc.typ))
+
+		details := []struct{ name, value string }{
+			{"Default", doc.defaultValue},
+			{"Environment", doc.env},
+			{"Flag", doc.flag},
+			{"File key", doc.fileKey},
+		}
+
+		for _, detail := range details {
+			if detail.value != "" {
+				_, _ = fmt.Fprintf(&buf, ".br\n%s: \\fB%s\\fR\n", detail.name, roffEscape(detail.value))
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// roffEscape escapes backslashes and dashes and prevents lines from being interpreted as roff requests.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`, "\n", " ").Replace(s)
+
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+
+	return s
+}
@@ -0,0 +1,58 @@
+package alligotor
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("docs", func() {
+	type config struct {
+		LogLevel string `description:"level | verbosity"`
+		DB       struct {
+			Host     string        `config:"flag=h host,file=hostname"`
+			Timeout  time.Duration `description:"connection timeout"`
+			Password Secret[string]
+		}
+	}
+
+	var cfg config
+
+	BeforeEach(func() {
+		cfg = config{LogLevel: "info"}
+		cfg.DB.Timeout = time.Minute
+		cfg.DB.Password = NewSecret("hunter2")
+	})
+
+	It("generates a markdown table", func() {
+		out, err := New(NewEnvSource("APP"), NewFlagsSource()).Docs(&cfg, DocsMarkdown)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal(
+			"| Field | Type | Default | Description | Env | Flag | File key |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| `LogLevel` | `string` | `info` | level \\| verbosity | `APP_LOGLEVEL` | `--loglevel` | `LogLevel` |\n" +
+				"| `DB.Host` | `string` |  |  | `APP_DB_HOST` | `--db.host, -h` | `DB.hostname` |\n" +
+				"| `DB.Timeout` | `time.Duration` | `1m0s` | connection timeout | `APP_DB_TIMEOUT` | `--db.timeout` | `DB.Timeout` |\n" +
+				"| `DB.Password` | `string` | `******` |  | `APP_DB_PASSWORD` | `--db.password` | `DB.Password` |\n",
+		))
+	})
+	It("leaves out columns for missing sources", func() {
+		out, err := New().Docs(&cfg, DocsMarkdown)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(HavePrefix("| Field | Type | Default | Description | File key |\n"))
+	})
+	It("generates a man page section", func() {
+		out, err := New(NewEnvSource("APP"), NewFlagsSource()).Docs(&cfg, DocsMan)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(HavePrefix(".SH CONFIGURATION\n"))
+		Expect(string(out)).To(ContainSubstring(".TP\n.B DB.Timeout\nconnection timeout\n.br\nType: time.Duration\n" +
+			".br\nDefault: \\fB1m0s\\fR\n.br\nEnvironment: \\fBAPP_DB_TIMEOUT\\fR\n.br\nFlag: \\fB\\-\\-db.timeout\\fR\n" +
+			".br\nFile key: \\fBDB.Timeout\\fR\n"))
+		Expect(string(out)).ToNot(ContainSubstring("hunter2"))
+	})
+	It("returns error for unknown formats", func() {
+		_, err := New().Docs(&cfg, "html")
+		Expect(err).To(MatchError(ErrUnknownDocsFormat))
+	})
+})
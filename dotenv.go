@@ -0,0 +1,209 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// DotEnvSource is used to read the configuration from .env files.
+// The variables are looked up with the same naming rules as the EnvSource, so the prefix, separator and
+// `config:"env=..."` overrides apply. The prefix and separator can be set with WithOptions.
+//
+// The files support comments, single and double-quoted values that can span multiple lines, the export prefix and
+// expansion of variables in the form ${VAR} or $VAR, except in single-quoted values.
+// Variables are expanded with the values defined before in the files or otherwise from the environment.
+type DotEnvSource struct {
+	paths []string
+	EnvSource
+}
+
+// NewDotEnvSource returns a new DotEnvSource that reads the files at the given paths.
+// Files that don't exist are skipped. If a variable is defined in multiple files, the last one wins.
+func NewDotEnvSource(paths ...string) *DotEnvSource {
+	return &DotEnvSource{
+		paths:     paths,
+		EnvSource: *NewEnvSource(""),
+	}
+}
+
+// WithOptions applies the given options like WithEnvPrefix or WithEnvSeparator to the underlying EnvSource.
+// It returns the DotEnvSource to be able to use it inline when defining a Collector's sources.
+func (s *DotEnvSource) WithOptions(opts ...EnvOption) *DotEnvSource {
+	for _, opt := range opts {
+		opt(&s.EnvSource)
+	}
+
+	return s
+}
+
+// Init reads and parses the files to initialize the underlying EnvSource.
+func (s *DotEnvSource) Init(fields []Field) error {
+	vars := map[string]string{}
+
+	for _, path := range s.paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := parseDotEnv(path, string(data), vars); err != nil {
+			return err
+		}
+	}
+
+	s.envMap = vars
+
+	return s.checkUnknown(fields)
+}
+
+// parseDotEnv parses the content of a .env file and adds the variables to vars.
+func parseDotEnv(name, content string, vars map[string]string) error {
+	lookup := func(key string) string {
+		if val, ok := vars[key]; ok {
+			return val
+		}
+
+		return os.Getenv(key)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+
+		if !ok || key == "" || strings.IndexFunc(key, unicode.IsSpace) >= 0 {
+			return &ParseError{File: name, Line: lineNumber, Err: fmt.Errorf("%w: expected KEY=value", ErrMalformedFile)}
+		}
+
+		rest = strings.TrimSpace(rest)
+
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			// inline comments need to be separated by whitespace in unquoted values
+			if index := strings.Index(rest, " #"); index >= 0 {
+				rest = rest[:index]
+			}
+
+			vars[key] = expandDotEnv(strings.TrimSpace(rest), false, lookup)
+
+			continue
+		}
+
+		// quoted values can span multiple lines until the closing quote is found
+		quote := rest[0]
+		value := rest[1:]
+
+		end := closingQuote(value, quote)
+		for end < 0 && i+1 < len(lines) {
+			i++
+			value += "\n" + lines[i]
+			end = closingQuote(value, quote)
+		}
+
+		if end < 0 {
+			return &ParseError{
+				File: name, Line: lineNumber, Key: key, Err: fmt.Errorf("%w: unterminated quote", ErrMalformedFile),
+			}
+		}
+
+		if quote == '\'' {
+			vars[key] = value[:end]
+		} else {
+			vars[key] = expandDotEnv(value[:end], true, lookup)
+		}
+	}
+
+	return nil
+}
+
+// closingQuote returns the index of the first unescaped quote in s or -1 if there is none.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// expandDotEnv replaces ${VAR} and $VAR with the values returned by lookup.
+// If escapes is true, escape sequences like \n, \" or \$ are handled like in double-quoted values.
+func expandDotEnv(s string, escapes bool, lookup func(string) string) string {
+	var result strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escapes && s[i] == '\\' && i+1 < len(s):
+			i++
+			result.WriteString(unescapeDotEnv(s[i]))
+		case s[i] == '$' && i+1 < len(s):
+			name, length := dotEnvVarName(s[i+1:])
+			if length == 0 {
+				result.WriteByte(s[i])
+				continue
+			}
+
+			result.WriteString(lookup(name))
+			i += length
+		default:
+			result.WriteByte(s[i])
+		}
+	}
+
+	return result.String()
+}
+
+func unescapeDotEnv(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	}
+
+	return string(c)
+}
+
+// dotEnvVarName returns the variable name at the start of s, which is the part after a "$", as well as
+// the number of bytes that belong to the reference. If there is no valid reference the length is 0.
+func dotEnvVarName(s string) (string, int) {
+	if strings.HasPrefix(s, "{") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", 0
+		}
+
+		return s[1:end], end + 1
+	}
+
+	length := 0
+	for length < len(s) && (s[length] == '_' || unicode.IsLetter(rune(s[length])) ||
+		(length > 0 && unicode.IsDigit(rune(s[length])))) {
+		length++
+	}
+
+	return s[:length], length
+}
@@ -0,0 +1,99 @@
+package alligotor
+
+import (
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("dotenv", func() {
+	Describe("parseDotEnv", func() {
+		var vars map[string]string
+		BeforeEach(func() {
+			vars = map[string]string{}
+		})
+		It("parses all supported syntax", func() {
+			Expect(os.Setenv("DOTENV_TEST_HOME", "/home/test")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "DOTENV_TEST_HOME")
+
+			content := `# comment
+PLAIN=value
+export EXPORTED=exported
+SPACES = value with spaces # inline comment
+HASH=value#nocomment
+SINGLE='no $PLAIN expansion'
+DOUBLE="escaped \"quotes\"\tand $PLAIN"
+MULTI="first
+second"
+SINGLE_MULTI='first
+second'
+BRACES=${PLAIN}_suffix
+FROM_ENV=$DOTENV_TEST_HOME/dir
+DOLLAR="\$PLAIN"
+EMPTY=
+`
+			Expect(parseDotEnv(".env", content, vars)).To(Succeed())
+			Expect(vars).To(Equal(map[string]string{
+				"PLAIN":        "value",
+				"EXPORTED":     "exported",
+				"SPACES":       "value with spaces",
+				"HASH":         "value#nocomment",
+				"SINGLE":       "no $PLAIN expansion",
+				"DOUBLE":       "escaped \"quotes\"\tand value",
+				"MULTI":        "first\nsecond",
+				"SINGLE_MULTI": "first\nsecond",
+				"BRACES":       "value_suffix",
+				"FROM_ENV":     "/home/test/dir",
+				"DOLLAR":       "$PLAIN",
+				"EMPTY":        "",
+			}))
+		})
+		It("returns errors for lines without =", func() {
+			err := parseDotEnv(".env", "A=b\ninvalid\n", vars)
+			Expect(err).To(MatchError(ErrMalformedFile))
+			Expect(err.Error()).To(HavePrefix(".env:2: "))
+		})
+		It("returns errors for unterminated quotes", func() {
+			err := parseDotEnv(".env", "A=\"b\nc\n", vars)
+			Expect(err).To(MatchError(ErrMalformedFile))
+			Expect(err.Error()).To(HavePrefix(".env:1: A: "))
+		})
+	})
+	Describe("DotEnvSource", func() {
+		var tmpDir string
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = os.MkdirTemp("", "tests*")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.WriteFile(path.Join(tmpDir, ".env"), []byte("APP_DB_HOST=first\nAPP_PORT=1\n"), os.ModePerm)).
+				To(Succeed())
+			Expect(os.WriteFile(path.Join(tmpDir, ".env.local"), []byte("APP_PORT=2\n"), os.ModePerm)).To(Succeed())
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		})
+		It("reads the fields with the env naming rules", func() {
+			cfg := struct {
+				DB struct {
+					Host string
+				}
+				Port int
+				Name string `config:"env=custom"`
+			}{Name: "default"}
+
+			c := New(NewDotEnvSource(
+				path.Join(tmpDir, ".env"),
+				path.Join(tmpDir, ".env.local"),
+				path.Join(tmpDir, ".env.missing"),
+			).WithOptions(WithEnvPrefix("app")))
+
+			Expect(c.Get(&cfg)).To(Succeed())
+			Expect(cfg.DB.Host).To(Equal("first"))
+			Expect(cfg.Port).To(Equal(2))
+			Expect(cfg.Name).To(Equal("default"))
+		})
+	})
+})
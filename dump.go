@@ -0,0 +1,311 @@
+package alligotor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DumpFormat defines the format that is used by Collector.Dump.
+type DumpFormat string
+
+const (
+	// DumpYAML dumps the config as a YAML document using the keys that are read by the ReadersSource.
+	DumpYAML DumpFormat = "yaml"
+	// DumpJSON dumps the config as a JSON document using the keys that are read by the ReadersSource.
+	DumpJSON DumpFormat = "json"
+	// DumpEnv dumps the config as NAME=value lines using the names that are read by the Collector's EnvSource.
+	DumpEnv DumpFormat = "env"
+	// DumpFlags dumps the config as --name=value lines using the names that are read by the Collector's FlagsSource.
+	DumpFlags DumpFormat = "flags"
+)
+
+var ErrUnknownDumpFormat = errors.New("unknown dump format")
+
+// Dump serializes the given config struct in the given format using the same keys the sources read from.
+// It can be seen as the inverse of the sources and is intended to be used after Get to print or snapshot
+// the effective configuration. Secrets are redacted.
+//
+// For DumpEnv the prefix and separator of the first EnvSource in the Collector's sources are used,
+// for DumpFlags the separator of the first FlagsSource. If there is none, the defaults are used.
+func (c *Collector) Dump(v interface{}, format DumpFormat) ([]byte, error) {
+	value, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := getFieldsConfigsFromValue(value, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := make([]*Field, 0, len(fields))
+
+	for _, f := range leafFields(fields) {
+		if f.value.Kind() != reflect.Ptr || !f.value.IsNil() {
+			leaves = append(leaves, f)
+		}
+	}
+
+	switch format {
+	case DumpYAML:
+		return yaml.Marshal(dumpTree(leaves))
+	case DumpJSON:
+		return json.MarshalIndent(dumpTree(leaves), "", "  ")
+	case DumpEnv:
+		env := c.envSource()
+
+		return dumpLines(leaves, func(f *Field) string {
+			if f.IgnoredBy(envKey) {
+				return ""
+			}
+
+			return envName(f, env.prefix, env.separator)
+		}), nil
+	case DumpFlags:
+		flags := c.flagsSource()
+
+		return dumpLines(leaves, func(f *Field) string {
+			if f.IgnoredBy(flagKey) {
+				return ""
+			}
+
+			return "--" + flagName(f, flags.separator)
+		}), nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", format, ErrUnknownDumpFormat)
+}
+
+// envSource returns the first EnvSource of the Collector or a default one.
+func (c *Collector) envSource() *EnvSource {
+	if env := c.findEnvSource(); env != nil {
+		return env
+	}
+
+	return NewEnvSource("")
+}
+
+// findEnvSource returns the first EnvSource of the Collector or nil if there is none.
+func (c *Collector) findEnvSource() *EnvSource {
+	for _, source := range c.Sources {
+		if env, ok := source.(*EnvSource); ok {
+			return env
+		}
+	}
+
+	return nil
+}
+
+// flagsSource returns the first FlagsSource of the Collector or a default one.
+func (c *Collector) flagsSource() *FlagsSource {
+	if flags := c.findFlagsSource(); flags != nil {
+		return flags
+	}
+
+	return NewFlagsSource()
+}
+
+// findFlagsSource returns the first FlagsSource of the Collector or nil if there is none.
+func (c *Collector) findFlagsSource() *FlagsSource {
+	for _, source := range c.Sources {
+		if flags, ok := source.(*FlagsSource); ok {
+			return flags
+		}
+	}
+
+	return nil
+}
+
+// leafFields filters the fields to the ones holding actual values.
+// Plain structs are only containers for their fields, while structs that implement encoding.TextMarshaler like
+// time.Time are values themselves, so their fields are skipped. Unexported fields are skipped too.
+func leafFields(fields []Field) []*Field {
+	leaves := make([]*Field, 0, len(fields))
+	valueStructs := map[string]bool{}
+
+	for i := range fields {
+		f := &fields[i]
+
+		if hasValueBase(f, valueStructs) || !f.value.CanInterface() {
+			continue
+		}
+
+		if f.value.Kind() == reflect.Struct && !isValueStruct(f.value) {
+			continue
+		}
+
+		if f.value.Kind() == reflect.Struct {
+			valueStructs[fieldPath(f)] = true
+		}
+
+		leaves = append(leaves, f)
+	}
+
+	return leaves
+}
+
+func hasValueBase(f *Field, valueStructs map[string]bool) bool {
+	base := f.Base()
+	for i := range base {
+		if valueStructs[fieldPath(&base[i])] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isValueStruct checks if a struct is handled as a single value since it can be marshaled to text.
+func isValueStruct(value reflect.Value) bool {
+	return value.Type().Implements(textMarshaler) ||
+		(value.CanAddr() && value.Addr().Type().Implements(textMarshaler))
+}
+
+// dumpValue returns the value of a field as it should be marshaled into YAML or JSON.
+func dumpValue(f *Field) interface{} {
+	if isSecret(f) {
+		return redacted
+	}
+
+	value := reflect.Indirect(f.value)
+
+	switch typedVal := value.Interface().(type) {
+	case time.Duration:
+		return typedVal.String()
+	case time.Time:
+		return typedVal.Format(time.RFC3339)
+	}
+
+	if isValueStruct(value) || value.Type().Implements(textMarshaler) {
+		return toString(value)
+	}
+
+	return value.Interface()
+}
+
+// dumpNode is a node in the tree of keys to be dumped. It keeps the order of the fields when marshaled.
+type dumpNode struct {
+	keys     []string
+	children map[string]*dumpNode
+	value    interface{}
+}
+
+func dumpTree(leaves []*Field) *dumpNode {
+	root := &dumpNode{children: map[string]*dumpNode{}}
+
+	for _, f := range leaves {
+		if f.IgnoredBy(fileKey) {
+			continue
+		}
+
+		node := root
+		for _, key := range append(f.BaseNames(extractFileName), extractFileName(f)) {
+			node = node.child(key)
+		}
+
+		node.value = dumpValue(f)
+	}
+
+	return root
+}
+
+func (n *dumpNode) child(key string) *dumpNode {
+	if child, ok := n.children[key]; ok {
+		return child
+	}
+
+	child := &dumpNode{children: map[string]*dumpNode{}}
+	n.keys = append(n.keys, key)
+	n.children[key] = child
+
+	return child
+}
+
+func (n *dumpNode) MarshalYAML() (interface{}, error) {
+	if len(n.keys) == 0 {
+		return n.value, nil
+	}
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, key := range n.keys {
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(n.children[key]); err != nil {
+			return nil, err
+		}
+
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+	}
+
+	return mapping, nil
+}
+
+func (n *dumpNode) MarshalJSON() ([]byte, error) {
+	if len(n.keys) == 0 {
+		return json.Marshal(n.value)
+	}
+
+	buf := bytes.NewBufferString("{")
+
+	for i, key := range n.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueBytes, err := json.Marshal(n.children[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(keyBytes)
+		buf.WriteString(":")
+		buf.Write(valueBytes)
+	}
+
+	buf.WriteString("}")
+
+	return buf.Bytes(), nil
+}
+
+// dumpLines writes one name=value line for every field using the given function to generate the name.
+func dumpLines(leaves []*Field, nameFunc func(*Field) string) []byte {
+	var buf bytes.Buffer
+
+	for _, f := range leaves {
+		name := nameFunc(f)
+		if name == "" {
+			continue
+		}
+
+		value := toString(f.value)
+		if isSecret(f) {
+			value = redacted
+		}
+
+		_, _ = fmt.Fprintf(&buf, "%s=%s\n", name, quoteIfNeeded(value))
+	}
+
+	return buf.Bytes()
+}
+
+// quoteIfNeeded quotes values that contain whitespace, quotes or comment characters.
+func quoteIfNeeded(value string) string {
+	if strings.ContainsAny(value, " \t\n\r\"'#\\$`") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
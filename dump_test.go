@@ -0,0 +1,113 @@
+package alligotor
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("dump", func() {
+	type config struct {
+		Name    string `config:"file=title,env=title,flag=title"`
+		Tags    []string
+		Labels  map[string]string
+		Created time.Time
+		Skipped *struct{ Port int }
+		DB      struct {
+			Timeout  time.Duration
+			Password Secret[string]
+			Token    string `config:"secret=true"`
+		}
+	}
+
+	var (
+		c   *Collector
+		cfg config
+	)
+
+	BeforeEach(func() {
+		c = New(NewEnvSource("APP"), NewFlagsSource(WithFlagSeparator("-")))
+		cfg = config{
+			Name:    "my app",
+			Tags:    []string{"a", "b"},
+			Labels:  map[string]string{"k": "v"},
+			Created: time.Date(2007, 01, 02, 15, 04, 05, 00, time.UTC),
+		}
+		cfg.DB.Timeout = time.Minute
+		cfg.DB.Password = NewSecret("hunter2")
+		cfg.DB.Token = "token"
+	})
+
+	It("dumps yaml", func() {
+		out, err := c.Dump(&cfg, DumpYAML)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal(`title: my app
+Tags:
+    - a
+    - b
+Labels:
+    k: v
+Created: "2007-01-02T15:04:05Z"
+DB:
+    Timeout: 1m0s
+    Password: '******'
+    Token: '******'
+`))
+	})
+	It("dumps json", func() {
+		out, err := c.Dump(&cfg, DumpJSON)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(MatchJSON(`{
+  "title": "my app",
+  "Tags": ["a", "b"],
+  "Labels": {"k": "v"},
+  "Created": "2007-01-02T15:04:05Z",
+  "DB": {"Timeout": "1m0s", "Password": "******", "Token": "******"}
+}`))
+	})
+	It("dumps env", func() {
+		out, err := c.Dump(&cfg, DumpEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal(`APP_TITLE="my app"
+APP_TAGS=a,b
+APP_LABELS=k=v
+APP_CREATED=2007-01-02T15:04:05Z
+APP_DB_TIMEOUT=1m0s
+APP_DB_PASSWORD=******
+APP_DB_TOKEN=******
+`))
+	})
+	It("dumps flags", func() {
+		out, err := c.Dump(&cfg, DumpFlags)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal(`--title="my app"
+--tags=a,b
+--labels=k=v
+--created=2007-01-02T15:04:05Z
+--db-timeout=1m0s
+--db-password=******
+--db-token=******
+`))
+	})
+	It("can be read again", func() {
+		cfg.DB.Password = Secret[string]{}
+		cfg.DB.Token = ""
+
+		out, err := c.Dump(&cfg, DumpYAML)
+		Expect(err).ToNot(HaveOccurred())
+
+		var read config
+		Expect(New(NewReadersSource(bytes.NewReader(out))).Get(&read)).To(Succeed())
+		Expect(read.Name).To(Equal(cfg.Name))
+		Expect(read.Tags).To(Equal(cfg.Tags))
+		Expect(read.Labels).To(Equal(cfg.Labels))
+		Expect(read.Created).To(Equal(cfg.Created))
+		Expect(read.DB.Timeout).To(Equal(cfg.DB.Timeout))
+	})
+	It("returns error for unknown formats", func() {
+		_, err := c.Dump(&cfg, "xml")
+		Expect(err).To(MatchError(ErrUnknownDumpFormat))
+	})
+})
@@ -1,8 +1,13 @@
 package alligotor
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -10,6 +15,13 @@ const (
 	defaultEnvSeparator = "_"
 )
 
+const fileEnvSuffix = "FILE"
+
+var (
+	ErrUnknownEnv      = errors.New("unknown environment variables")
+	ErrEnvFileConflict = errors.New("both the variable and its file variant are set")
+)
+
 // EnvSource is used to read the configuration from environment variables.
 // prefix can be defined to look for environment variables with a certain prefix.
 // separator is used for nested structs and also for the Prefix.
@@ -20,6 +32,14 @@ type EnvSource struct {
 	prefix    string
 	separator string
 	envMap    map[string]string
+	// unknownHandler is called for every environment variable with the prefix that doesn't belong to a field
+	unknownHandler func(UnknownEnv)
+	// disallowUnknown makes Init fail on environment variables with the prefix that don't belong to a field
+	disallowUnknown bool
+	// fileEnv enables reading values from the files referenced by variables with the _FILE suffix
+	fileEnv bool
+	// interpolate enables the expansion of variables in the values
+	interpolate bool
 }
 
 // NewEnvSource returns a new EnvSource.
@@ -50,28 +70,280 @@ func WithEnvSeparator(separator string) EnvOption {
 	}
 }
 
+// WithEnvPrefix sets the prefix of an EnvSource. It's mainly useful for sources that embed an EnvSource like
+// the DotEnvSource.
+func WithEnvPrefix(prefix string) EnvOption {
+	return func(env *EnvSource) {
+		env.prefix = prefix
+	}
+}
+
+// WithFileEnv enables reading values from files referenced by environment variables with the suffix "_FILE"
+// (or rather the separator followed by "FILE"), e.g. APP_DB_PASSWORD_FILE=/run/secrets/db for the field DB.Password.
+// This is a common convention for secrets in Docker and Kubernetes. Trailing newlines of the file's content are removed.
+// If both the variable and its file variant are set, Read returns an ErrEnvFileConflict.
+func WithFileEnv() EnvOption {
+	return func(env *EnvSource) {
+		env.fileEnv = true
+	}
+}
+
+// WithUnknownEnvHandler sets a handler that is called in Init for every environment variable that starts with the
+// EnvSource's prefix but doesn't correspond to any field, e.g. to log a warning.
+// It has no effect if the EnvSource has no prefix.
+func WithUnknownEnvHandler(handler func(UnknownEnv)) EnvOption {
+	return func(env *EnvSource) {
+		env.unknownHandler = handler
+	}
+}
+
+// WithDisallowUnknownEnv makes Init return an *UnknownEnvError if any environment variable starts with the
+// EnvSource's prefix but doesn't correspond to any field.
+// It has no effect if the EnvSource has no prefix.
+func WithDisallowUnknownEnv() EnvOption {
+	return func(env *EnvSource) {
+		env.disallowUnknown = true
+	}
+}
+
+// UnknownEnv describes an environment variable that starts with the EnvSource's prefix but doesn't correspond
+// to any field.
+type UnknownEnv struct {
+	Name string
+	// Suggestion contains the most similar expected name or is empty if there is none.
+	Suggestion string
+}
+
+func (u UnknownEnv) String() string {
+	if u.Suggestion == "" {
+		return u.Name
+	}
+
+	return fmt.Sprintf("%s (did you mean %s?)", u.Name, u.Suggestion)
+}
+
+// UnknownEnvError is returned by the EnvSource's Init if WithDisallowUnknownEnv is used and there are
+// environment variables with the prefix that don't correspond to any field.
+type UnknownEnvError struct {
+	Vars []UnknownEnv
+}
+
+func (e *UnknownEnvError) Error() string {
+	vars := make([]string, 0, len(e.Vars))
+	for _, v := range e.Vars {
+		vars = append(vars, v.String())
+	}
+
+	return fmt.Sprintf("%s: %s", ErrUnknownEnv, strings.Join(vars, "; "))
+}
+
+// Unwrap makes it possible to check for ErrUnknownEnv using errors.Is.
+func (e *UnknownEnvError) Unwrap() error {
+	return ErrUnknownEnv
+}
+
 // Init initializes the envMap property.
 // It should be used right before calling the Read method to load the latest environment variables.
-func (s *EnvSource) Init(_ []Field) error {
+// If configured, environment variables with the prefix that don't correspond to any field are reported.
+func (s *EnvSource) Init(fields []Field) error {
 	s.envMap = getEnvAsMap()
+
+	return s.checkUnknown(fields)
+}
+
+// checkUnknown reports the variables in envMap that start with the prefix but don't correspond to any field
+// as configured with WithUnknownEnvHandler and WithDisallowUnknownEnv.
+func (s *EnvSource) checkUnknown(fields []Field) error {
+	if s.prefix == "" || (s.unknownHandler == nil && !s.disallowUnknown) {
+		return nil
+	}
+
+	knownNames := make([]string, 0, len(fields))
+
+	var indexedPrefixes []string
+
+	for i := range fields {
+		// variables of ignored fields are not read and therefore reported as unknown
+		if fields[i].IgnoredBy(envKey) {
+			continue
+		}
+
+		name := envName(&fields[i], s.prefix, s.separator)
+		knownNames = append(knownNames, name)
+
+		if isIndexable(fields[i].value) {
+			indexedPrefixes = append(indexedPrefixes, name+s.separator)
+		}
+
+		if s.fileEnv {
+			knownNames = append(knownNames, name+s.separator+fileEnvSuffix)
+		}
+	}
+
+	unknown := unknownEnv(knownNames, indexedPrefixes, s.envMap, s.prefix, s.separator)
+
+	if s.unknownHandler != nil {
+		for _, u := range unknown {
+			s.unknownHandler(u)
+		}
+	}
+
+	if s.disallowUnknown && len(unknown) > 0 {
+		return &UnknownEnvError{Vars: unknown}
+	}
+
 	return nil
 }
 
 // Read reads the saved environment variables from the Init function and returns the set value for a certain field.
 // If not value is set in the flags it returns nil.
+// If WithFileEnv is used, the value is read from the file referenced by the variable's file variant instead.
+// If WithEnvInterpolation is used, variables in the value are expanded.
 func (s *EnvSource) Read(field *Field) (interface{}, error) {
-	return readEnv(field, s.prefix, s.envMap, s.separator), nil
+	if field.IgnoredBy(envKey) {
+		return nil, nil
+	}
+
+	val, err := s.read(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if val != nil && s.interpolate {
+		expanded, err := expandVars(string(val), s.lookupVar)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", envName(field, s.prefix, s.separator), err)
+		}
+
+		val = []byte(expanded)
+	}
+
+	if !isIndexable(field.value) {
+		return val, nil
+	}
+
+	return s.readIndexed(field, val)
 }
 
-func readEnv(f *Field, prefix string, envMap map[string]string, separator string) []byte {
-	name := extractEnvName(f)
+// readIndexed reads slices from indexed environment variables like APP_SERVERS_0_HOST.
+// val is the value of the slice's own variable, which is used as the base for the indexed variables.
+// If there are no indexed variables, val is returned.
+func (s *EnvSource) readIndexed(field *Field, val []byte) (interface{}, error) {
+	current := field.value
+	merge := mergeSlices(field)
 
-	distinctEnvName := strings.Join(append(f.BaseNames(extractEnvName), name), separator)
-	if prefix != "" {
-		distinctEnvName = prefix + separator + distinctEnvName
+	if val != nil {
+		current = reflect.New(field.Type()).Elem()
+		if err := setSeparated(current, val, fieldSeparators(field)); err != nil {
+			return nil, err
+		}
+
+		merge = true
+	}
+
+	keys := indexedKeys{
+		separator: s.separator,
+		values:    s.envMap,
+		relName: func(f *Field) string {
+			return envName(f, "", s.separator)
+		},
+	}
+
+	slice, ok, err := keys.readSlice(current, envName(field, s.prefix, s.separator), merge)
+	if err != nil || !ok {
+		return val, err
+	}
+
+	return slice.Interface(), nil
+}
+
+// lookupVar looks up variables for the interpolation in the envMap and afterwards in the environment.
+func (s *EnvSource) lookupVar(name string) (string, bool, error) {
+	if val, ok := s.envMap[name]; ok {
+		return val, true, nil
+	}
+
+	val, ok := os.LookupEnv(name)
+
+	return val, ok, nil
+}
+
+// read returns the value for the field either from the variable or from the file referenced by its file variant.
+func (s *EnvSource) read(field *Field) ([]byte, error) {
+	val := readEnv(field, s.prefix, s.envMap, s.separator)
+	if !s.fileEnv {
+		return val, nil
+	}
+
+	name := envName(field, s.prefix, s.separator)
+	fileName := name + s.separator + fileEnvSuffix
+
+	path, ok := s.envMap[fileName]
+	if !ok {
+		return val, nil
+	}
+
+	if val != nil {
+		return nil, fmt.Errorf("%s, %s: %w", name, fileName, ErrEnvFileConflict)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	return []byte(strings.TrimRight(string(content), "\r\n")), nil
+}
+
+// Locate returns the name of the environment variable that is looked up for a certain field.
+func (s *EnvSource) Locate(field *Field) string {
+	if field.IgnoredBy(envKey) {
+		return ""
+	}
+
+	return envName(field, s.prefix, s.separator)
+}
+
+// unknownEnv returns all environment variables starting with the prefix that are neither part of the known names
+// nor indexed variables for slices.
+func unknownEnv(knownNames, indexedPrefixes []string, envMap map[string]string, prefix, separator string) []UnknownEnv {
+	known := make(map[string]bool, len(knownNames))
+	for _, name := range knownNames {
+		known[name] = true
 	}
 
-	envVal, ok := envMap[strings.ToUpper(distinctEnvName)]
+	envPrefix := strings.ToUpper(prefix + separator)
+
+	var unknown []UnknownEnv
+
+	for name := range envMap {
+		if known[name] || !strings.HasPrefix(strings.ToUpper(name), envPrefix) || isIndexedName(name, indexedPrefixes) {
+			continue
+		}
+
+		unknown = append(unknown, UnknownEnv{Name: name, Suggestion: suggest(name, knownNames)})
+	}
+
+	sort.Slice(unknown, func(i, j int) bool {
+		return unknown[i].Name < unknown[j].Name
+	})
+
+	return unknown
+}
+
+// isIndexedName checks if the name is an indexed variable for a slice, i.e. one of the prefixes followed by a digit.
+func isIndexedName(name string, indexedPrefixes []string) bool {
+	for _, prefix := range indexedPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) && unicode.IsDigit(rune(name[len(prefix)])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readEnv(f *Field, prefix string, envMap map[string]string, separator string) []byte {
+	envVal, ok := envMap[envName(f, prefix, separator)]
 	if !ok {
 		return nil
 	}
@@ -79,6 +351,18 @@ func readEnv(f *Field, prefix string, envMap map[string]string, separator string
 	return []byte(envVal)
 }
 
+// envName returns the distinct environment variable name for a field including the prefix.
+func envName(f *Field, prefix, separator string) string {
+	name := extractEnvName(f)
+
+	distinctEnvName := strings.Join(append(f.BaseNames(extractEnvName), name), separator)
+	if prefix != "" {
+		distinctEnvName = prefix + separator + distinctEnvName
+	}
+
+	return strings.ToUpper(distinctEnvName)
+}
+
 func extractEnvName(f *Field) string {
 	if f.Configs()[envKey] != "" {
 		return f.Configs()[envKey]
@@ -2,6 +2,7 @@ package alligotor
 
 import (
 	"os"
+	"path"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -106,4 +107,110 @@ var _ = Describe("env", func() {
 			})
 		})
 	})
+	Describe("Locate", func() {
+		It("returns the env name including prefix and base", func() {
+			s := NewEnvSource("prefix")
+			field := &Field{name: "name", base: []Field{{name: "base", configs: map[string]string{envKey: "overwrite"}}}}
+			Expect(s.Locate(field)).To(Equal("PREFIX_OVERWRITE_NAME"))
+		})
+	})
+	Describe("file env", func() {
+		var (
+			field   *Field
+			tmpFile string
+		)
+		BeforeEach(func() {
+			field = &Field{name: "password", base: []Field{{name: "db"}}}
+			tmpFile = path.Join(GinkgoT().TempDir(), "password")
+			Expect(os.WriteFile(tmpFile, []byte("secret\n"), os.ModePerm)).To(Succeed())
+		})
+		It("reads the value from the referenced file without trailing newlines", func() {
+			s := NewEnvSource("app", WithFileEnv())
+			s.envMap = map[string]string{"APP_DB_PASSWORD_FILE": tmpFile}
+
+			val, err := s.Read(field)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(Equal([]byte("secret")))
+		})
+		It("ignores the file variant if not enabled", func() {
+			s := NewEnvSource("app")
+			s.envMap = map[string]string{"APP_DB_PASSWORD_FILE": tmpFile}
+
+			val, err := s.Read(field)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(BeNil())
+		})
+		It("returns an error if both variables are set", func() {
+			s := NewEnvSource("app", WithFileEnv())
+			s.envMap = map[string]string{"APP_DB_PASSWORD_FILE": tmpFile, "APP_DB_PASSWORD": "other"}
+
+			_, err := s.Read(field)
+			Expect(err).To(MatchError(ErrEnvFileConflict))
+		})
+		It("returns an error if the file does not exist", func() {
+			s := NewEnvSource("app", WithFileEnv())
+			s.envMap = map[string]string{"APP_DB_PASSWORD_FILE": tmpFile + "-missing"}
+
+			_, err := s.Read(field)
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+	Describe("unknown env", func() {
+		var fields []Field
+		BeforeEach(func() {
+			base := Field{name: "db"}
+			fields = []Field{base, {name: "host_name", base: []Field{base}}, {name: "port", base: []Field{base}}}
+
+			Expect(os.Setenv("APP_DB_HOSTNAME", "test")).To(Succeed())
+			Expect(os.Setenv("APP_DB_PORT", "1234")).To(Succeed())
+			Expect(os.Setenv("APP_OTHER", "value")).To(Succeed())
+		})
+		AfterEach(func() {
+			Expect(os.Unsetenv("APP_DB_HOSTNAME")).To(Succeed())
+			Expect(os.Unsetenv("APP_DB_PORT")).To(Succeed())
+			Expect(os.Unsetenv("APP_OTHER")).To(Succeed())
+		})
+		It("calls the handler for every unknown variable with the prefix", func() {
+			var unknown []UnknownEnv
+			s := NewEnvSource("app", WithUnknownEnvHandler(func(u UnknownEnv) {
+				unknown = append(unknown, u)
+			}))
+
+			Expect(s.Init(fields)).To(Succeed())
+			Expect(unknown).To(Equal([]UnknownEnv{
+				{Name: "APP_DB_HOSTNAME", Suggestion: "APP_DB_HOST_NAME"},
+				{Name: "APP_OTHER"},
+			}))
+		})
+		It("returns an error if unknown variables are disallowed", func() {
+			s := NewEnvSource("app", WithDisallowUnknownEnv())
+
+			err := s.Init(fields)
+			Expect(err).To(MatchError(ErrUnknownEnv))
+			Expect(err).To(MatchError(
+				"unknown environment variables: APP_DB_HOSTNAME (did you mean APP_DB_HOST_NAME?); APP_OTHER",
+			))
+		})
+		It("accepts file variants if enabled", func() {
+			Expect(os.Setenv("APP_DB_PORT_FILE", "/tmp/port")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "APP_DB_PORT_FILE")
+
+			s := NewEnvSource("app", WithDisallowUnknownEnv(), WithFileEnv())
+			Expect(s.Init(fields)).To(MatchError(
+				"unknown environment variables: APP_DB_HOSTNAME (did you mean APP_DB_HOST_NAME?); APP_OTHER",
+			))
+		})
+		It("reports variables of ignored fields", func() {
+			fields[2].configs = map[string]string{envKey: ignoreValue}
+
+			s := NewEnvSource("app", WithDisallowUnknownEnv())
+			Expect(s.Init(fields)).To(MatchError(
+				"unknown environment variables: APP_DB_HOSTNAME (did you mean APP_DB_HOST_NAME?); APP_DB_PORT; APP_OTHER",
+			))
+		})
+		It("ignores unknown variables without prefix", func() {
+			s := NewEnvSource("", WithDisallowUnknownEnv())
+			Expect(s.Init(fields)).To(Succeed())
+		})
+	})
 })
@@ -1,15 +1,20 @@
 package alligotor
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type (
 	globFunc = func(pattern string) ([]string, error)
 	openFunc = func(path string) (io.Reader, error)
+	statFunc = func(path string) (fs.FileInfo, error)
 )
 
 // FilesSource is a wrapper around ReadersSource to automatically find the needed readers in a filesystem.
@@ -25,9 +30,28 @@ type FilesSource struct {
 	// openFunc is used to open a file in the selected file system
 	// The used implementations are fsys.Open and os.Open
 	openFunc openFunc
+	// statFunc is used to get a file's info in the selected file system to detect changes
+	// The used implementations are fs.Stat and os.Stat
+	statFunc statFunc
+	// pollInterval is the interval in which the files are checked for changes in Watch
+	pollInterval time.Duration
 	ReadersSource
 }
 
+// WithPollInterval sets the interval in which the files are checked for changes when used in Collector.Watch.
+// It returns the FilesSource to be able to use it inline when defining a Collector's sources.
+func (s *FilesSource) WithPollInterval(interval time.Duration) *FilesSource {
+	s.pollInterval = interval
+	return s
+}
+
+// WithOptions applies the given options to the underlying ReadersSource.
+// It returns the FilesSource to be able to use it inline when defining a Collector's sources.
+func (s *FilesSource) WithOptions(opts ...ReadersOption) *FilesSource {
+	s.ReadersSource.WithOptions(opts...)
+	return s
+}
+
 // Init tries to find files on the filesystem matching the supplied globs and reads them.
 // Afterwards the underlying ReadersSource is initialized.
 func (s *FilesSource) Init(fields []Field) error {
@@ -36,11 +60,42 @@ func (s *FilesSource) Init(fields []Field) error {
 		return err
 	}
 
-	s.ReadersSource = *NewReadersSource(files...)
+	s.ReadersSource = ReadersSource{readers: files, options: s.options}
 
 	return s.ReadersSource.Init(fields)
 }
 
+// Watch polls the files matching the globs and notifies the changed channel if files are added, removed or modified.
+func (s *FilesSource) Watch(ctx context.Context, changed chan<- struct{}) error {
+	poll(ctx, s.pollInterval, changed, s.filesState)
+	return nil
+}
+
+// filesState returns a string describing the current state of all files that match the globs.
+// If any file is added, removed or modified the state changes.
+func (s *FilesSource) filesState() string {
+	var state strings.Builder
+
+	for _, glob := range s.globs {
+		matches, err := s.globFunc(glob)
+		if err != nil {
+			continue
+		}
+
+		for _, match := range matches {
+			info, err := s.statFunc(match)
+			if err != nil {
+				_, _ = fmt.Fprintf(&state, "%s:%s\n", match, err)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(&state, "%s:%d:%d\n", match, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+
+	return state.String()
+}
+
 // loadFiles tries to find files that match the globs using the globF function.
 // If any matches are found it then opens the file using the openF function and returns the opened files.
 func loadFiles(globs []string, globF globFunc, openF openFunc) ([]io.Reader, error) {
@@ -58,7 +113,7 @@ func loadFiles(globs []string, globF globFunc, openF openFunc) ([]io.Reader, err
 				return nil, err
 			}
 
-			files = append(files, file)
+			files = append(files, newNamedReader(file, match))
 		}
 	}
 
@@ -74,6 +129,10 @@ func NewFSFilesSource(fsys fs.FS, globs ...string) *FilesSource {
 		openFunc: func(path string) (io.Reader, error) {
 			return fsys.Open(path)
 		},
+		statFunc: func(path string) (fs.FileInfo, error) {
+			return fs.Stat(fsys, path)
+		},
+		pollInterval: defaultPollInterval,
 	}
 }
 
@@ -84,5 +143,36 @@ func NewFilesSource(globs ...string) *FilesSource {
 		openFunc: func(path string) (io.Reader, error) {
 			return os.Open(path)
 		},
+		statFunc:     os.Stat,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// namedReader wraps an io.Reader to attach the path it was opened from.
+// It is used by the ReadersSource to report where a value was read from.
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func newNamedReader(r io.Reader, name string) io.Reader {
+	if closer, ok := r.(io.ReadCloser); ok {
+		return &namedReadCloser{namedReader: namedReader{Reader: r, name: name}, closer: closer}
 	}
+
+	return &namedReader{Reader: r, name: name}
+}
+
+func (r *namedReader) Name() string {
+	return r.name
+}
+
+// namedReadCloser is a namedReader that also passes through the Close method of the wrapped reader.
+type namedReadCloser struct {
+	namedReader
+	closer io.Closer
+}
+
+func (r *namedReadCloser) Close() error {
+	return r.closer.Close()
 }
@@ -63,6 +63,7 @@ var _ = Describe("files", func() {
 				fstest.MapFS(map[string]*fstest.MapFile{
 					"test.json": {Data: []byte(`{"test":"json"}`)},
 					"test.yml":  {Data: []byte("test: yml")},
+					"test.toml": {Data: []byte(`test = "toml"`)},
 				}),
 				"test.*",
 			)
@@ -71,9 +72,20 @@ var _ = Describe("files", func() {
 			Expect(s.Init(nil)).To(Succeed())
 			Expect(s.fileMaps).To(Equal([]*ciMap{
 				{m: map[string]interface{}{"test": "json"}},
+				{m: map[string]interface{}{"test": "toml"}},
 				{m: map[string]interface{}{"test": "yml"}},
 			}))
 		})
+		It("keeps the options when initialized", func() {
+			s = NewFSFilesSource(
+				fstest.MapFS(map[string]*fstest.MapFile{"test.conf": {Data: []byte("no config")}}),
+				"test.*",
+			).WithOptions(WithStrictParsing())
+
+			err := s.Init(nil)
+			Expect(err).To(MatchError(ErrFileFormatNotSupported))
+			Expect(err.Error()).To(HavePrefix("test.conf: "))
+		})
 	})
 	Describe("NewFilesSource", func() {
 		var (
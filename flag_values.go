@@ -0,0 +1,216 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	countKey       = "count"
+	countIncrement = "+1"
+	negationPrefix = "no-"
+)
+
+var ErrInvalidCountFlag = errors.New("only integer fields can be count flags")
+
+// flagKind defines how a flagValue handles the values it is set to.
+type flagKind int
+
+const (
+	// plainFlag keeps the last value it is set to.
+	plainFlag flagKind = iota
+	// boolFlag can be set without a value and be negated with --no-<name>.
+	boolFlag
+	// countFlag is incremented every time it's set without a value, e.g. -vvv results in 3.
+	countFlag
+	// repeatedFlag collects all values, e.g. --tag a --tag b for slices or --label a=1 --label b=2 for maps.
+	repeatedFlag
+)
+
+// flagValue implements pflag.Value for a field. The values are validated by parsing them into the field's type,
+// but only the raw strings are kept and returned by Read, so that they are parsed just like the values of all other
+// sources afterwards.
+type flagValue struct {
+	typ     reflect.Type
+	kind    flagKind
+	seps    separators
+	def     string
+	values  []string
+	changed bool
+	// secret values are not validated since the errors would contain the value
+	secret bool
+}
+
+// newFlagValue returns a new flagValue for the field showing def as the default value.
+func newFlagValue(f *Field, def string) (*flagValue, error) {
+	value := &flagValue{
+		typ:    stringType,
+		kind:   plainFlag,
+		seps:   fieldSeparators(f),
+		def:    def,
+		secret: isSecret(f),
+	}
+
+	// fields without a value are read as plain strings
+	if f.value.IsValid() {
+		value.typ = f.Type()
+	}
+
+	elemType := value.typ
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	switch {
+	case f.Configs()[countKey] == "true":
+		if !isIntKind(elemType.Kind()) {
+			return nil, fmt.Errorf("%s: %w", fieldPath(f), ErrInvalidCountFlag)
+		}
+
+		value.kind = countFlag
+	case elemType.Kind() == reflect.Bool:
+		value.kind = boolFlag
+	case isRepeatable(elemType):
+		value.kind = repeatedFlag
+	}
+
+	return value, nil
+}
+
+// noOptDefVal returns the value that is used if the flag is set without a value, e.g. --enabled.
+func (v *flagValue) noOptDefVal() string {
+	//nolint:exhaustive // other flags always need a value
+	switch v.kind {
+	case boolFlag:
+		return "true"
+	case countFlag:
+		return countIncrement
+	}
+
+	return ""
+}
+
+func (v *flagValue) String() string {
+	if !v.changed {
+		return v.def
+	}
+
+	return strings.Join(v.values, v.seps.withDefaults().elem)
+}
+
+func (v *flagValue) Set(s string) error {
+	if v.kind == countFlag && s == countIncrement {
+		count := 0
+		if len(v.values) > 0 {
+			count, _ = strconv.Atoi(v.values[0])
+		}
+
+		s = strconv.Itoa(count + 1)
+	}
+
+	if !v.secret {
+		if _, err := parseString(reflect.New(v.typ).Elem(), s, v.seps); err != nil {
+			return err
+		}
+	}
+
+	if v.kind == repeatedFlag {
+		v.values = append(v.values, s)
+	} else {
+		v.values = []string{s}
+	}
+
+	v.changed = true
+
+	return nil
+}
+
+// Type returns the name of the field's Go type, which is shown in the usage.
+func (v *flagValue) Type() string {
+	return displayType(v.typ).String()
+}
+
+// negatedFlagValue implements pflag.Value for the --no-<name> flag of boolean flags.
+type negatedFlagValue struct {
+	target *flagValue
+}
+
+func (v *negatedFlagValue) String() string {
+	return ""
+}
+
+func (v *negatedFlagValue) Set(s string) error {
+	negated, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+
+	return v.target.Set(strconv.FormatBool(!negated))
+}
+
+func (v *negatedFlagValue) Type() string {
+	return "bool"
+}
+
+// isRepeatable checks if flags for the type can be repeated to collect multiple values,
+// which is the case for slices and maps that can be parsed from separated strings.
+func isRepeatable(t reflect.Type) bool {
+	if implementsTextUnmarshaler(t) {
+		return false
+	}
+
+	//nolint:exhaustive // only slices and maps can be repeated
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8 && isSplittable(t.Elem())
+	case reflect.Map:
+		return isSplittable(t.Key()) && isSplittable(t.Elem())
+	}
+
+	return false
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	//nolint:exhaustive // only checks for integers
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+// goFlagValue wraps a pflag.Value to be used in the standard library's flag package.
+type goFlagValue struct {
+	value pflag.Value
+}
+
+func (v goFlagValue) String() string {
+	if v.value == nil {
+		return ""
+	}
+
+	return v.value.String()
+}
+
+func (v goFlagValue) Set(s string) error {
+	return v.value.Set(s)
+}
+
+// IsBoolFlag makes it possible to set boolean flags without a value like --enabled.
+func (v goFlagValue) IsBoolFlag() bool {
+	switch typedVal := v.value.(type) {
+	case *flagValue:
+		return typedVal.kind == boolFlag
+	case *negatedFlagValue:
+		return true
+	}
+
+	return false
+}
@@ -2,8 +2,11 @@ package alligotor
 
 import (
 	"errors"
+	goflag "flag"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -18,6 +21,8 @@ const (
 var (
 	ErrMalformedFlagConfig = errors.New("malformed flag config strings")
 	ErrHelp                = errors.New("help requested")
+	ErrFlagConflict        = errors.New("flag is already defined by the application")
+	ErrNoFlagSet           = errors.New("no flag set configured")
 )
 
 // FlagsSource is used to read the configuration from command line flags.
@@ -25,6 +30,20 @@ var (
 type FlagsSource struct {
 	separator       string
 	fieldToFlagInfo map[string]*flagInfo
+	// indexedFlags contains the flags for elements of slices like --servers.0.host that were found in the arguments
+	indexedFlags map[string]*pflag.Flag
+	// flagSet is the flag set the flags are registered in. If it's nil, a new one is created for every Init.
+	flagSet *pflag.FlagSet
+	// goFlagSet is the standard library's flag set the flags are additionally registered in and that is parsed.
+	goFlagSet *goflag.FlagSet
+	// args are the arguments that are parsed. If they are nil, os.Args[1:] is used.
+	args []string
+	// remaining contains the positional arguments that are left after parsing.
+	remaining []string
+	// usageWriter is the writer the help is written to. If it's nil, os.Stderr is used.
+	usageWriter io.Writer
+	// envHint is the EnvSource whose variable names are shown in the help, see WithEnvHints.
+	envHint *EnvSource
 }
 
 // NewFlagsSource returns a new FlagsSource.
@@ -52,10 +71,69 @@ func WithFlagSeparator(separator string) FlagOption {
 	}
 }
 
+// WithFlagSet registers the flags in the given flag set instead of a new one, so that they can be combined with
+// the application's own flags, e.g. the ones of a cobra command. If the flag set is already parsed when
+// Collector.Get is called, the parsed values are used. Otherwise, the flag set is parsed with the arguments.
+// Use FlagsSource.RegisterFlags to register the flags before the application parses the flag set.
+func WithFlagSet(flagSet *pflag.FlagSet) FlagOption {
+	return func(source *FlagsSource) {
+		source.flagSet = flagSet
+	}
+}
+
+// WithGoFlagSet registers the flags in the given flag set of the standard library's flag package.
+// It works just like WithFlagSet. Since the standard library's flags don't support short names on their own, they are
+// registered as separate flags. Indexed flags for slices like --servers.0.host are not supported.
+func WithGoFlagSet(flagSet *goflag.FlagSet) FlagOption {
+	return func(source *FlagsSource) {
+		source.goFlagSet = flagSet
+		source.flagSet = pflag.NewFlagSet(flagSet.Name(), pflag.ContinueOnError)
+	}
+}
+
+// WithArgs sets the arguments that are parsed instead of os.Args[1:].
+func WithArgs(args []string) FlagOption {
+	return func(source *FlagsSource) {
+		source.args = args
+	}
+}
+
 // Init initializes the fieldToFlagInfos property.
 // It should be used right before calling the Read method to load the latest flags.
 func (s *FlagsSource) Init(fields []Field) error {
-	return s.initFlagMap(fields, os.Args[1:])
+	args := s.args
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	return s.initFlagMap(fields, args)
+}
+
+// RegisterFlags registers the flags for the config struct v in the flag set configured with WithFlagSet or
+// WithGoFlagSet without parsing it. This makes it possible to let the application parse its flag set,
+// e.g. by executing a cobra command, before calling Collector.Get.
+func (s *FlagsSource) RegisterFlags(v interface{}) error {
+	if s.flagSet == nil {
+		return ErrNoFlagSet
+	}
+
+	value, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	// register the flags of nil pointers to structs with defaults without modifying v
+	fields, err := getFieldsConfigsFromValue(copyWithDefaultStructs(value), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.registerFlags(s.flagSet, fields)
+}
+
+// Args returns the positional arguments that are left after parsing the flags in Collector.Get.
+func (s *FlagsSource) Args() []string {
+	return s.remaining
 }
 
 // Read reads the saved flagSet from the Init function and returns the set value for a certain field.
@@ -66,69 +144,281 @@ func (s *FlagsSource) Read(field *Field) (interface{}, error) {
 		return nil, nil
 	}
 
-	if !flagInfo.flag.Changed {
-		return nil, nil
+	var val []byte
+	if flagInfo.value.changed {
+		val = []byte(flagInfo.value.String())
+	}
+
+	if !isIndexable(field.value) {
+		return val, nil
 	}
 
-	return []byte(*flagInfo.valueStr), nil
+	return s.readIndexed(field, val)
+}
+
+// readIndexed reads slices from indexed flags like --servers.0.host.
+// val is the value of the slice's own flag, which is used as the base for the indexed flags.
+// If there are no indexed flags, val is returned.
+func (s *FlagsSource) readIndexed(field *Field, val []byte) (interface{}, error) {
+	current := field.value
+	merge := mergeSlices(field)
+
+	if val != nil {
+		current = reflect.New(field.Type()).Elem()
+		if err := setSeparated(current, val, fieldSeparators(field)); err != nil {
+			return nil, err
+		}
+
+		merge = true
+	}
+
+	values := map[string]string{}
+
+	for name, f := range s.indexedFlags {
+		if f.Changed {
+			values[name] = f.Value.String()
+		}
+	}
+
+	keys := indexedKeys{
+		separator: s.separator,
+		values:    values,
+		relName: func(f *Field) string {
+			return flagName(f, s.separator)
+		},
+	}
+
+	slice, ok, err := keys.readSlice(current, flagName(field, s.separator), merge)
+	if err != nil || !ok {
+		return val, err
+	}
+
+	return slice.Interface(), nil
 }
 
 type flagInfo struct {
-	valueStr *string
-	flag     *pflag.Flag
+	value *flagValue
+	flag  *pflag.Flag
 }
 
 func (s *FlagsSource) initFlagMap(fields []Field, args []string) error {
+	flagSet := s.flagSet
+	if flagSet == nil {
+		flagSet = newFlagSet()
+		flagSet.Usage = func() {
+			s.writeUsage(fields)
+		}
+	}
+
+	if err := s.registerFlags(flagSet, fields); err != nil {
+		return err
+	}
+
+	if s.goFlagSet != nil {
+		return s.parseGoFlags(fields, args)
+	}
+
+	// flag sets that were already parsed by the application, e.g. by cobra, are only read
+	if flagSet.Parsed() && s.flagSet != nil {
+		s.indexedFlags = map[string]*pflag.Flag{}
+		s.remaining = flagSet.Args()
+
+		return nil
+	}
+
+	s.registerIndexedFlags(flagSet, fields, args)
+
+	if err := flagSet.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return &HelpError{Usage: s.renderHelp(fields)}
+		}
+
+		return err
+	}
+
+	s.remaining = flagSet.Args()
+
+	return nil
+}
+
+// newFlagSet returns the flag set that is used if no flag set is configured with WithFlagSet.
+func newFlagSet() *pflag.FlagSet {
 	flagSet := pflag.NewFlagSet("config", pflag.ContinueOnError)
 	flagSet.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: false}
-	flagSet.Usage = func() {
-		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
-
-		flagSet.VisitAll(func(f *pflag.Flag) {
-			line := ""
-			if f.Shorthand != "" {
-				line = fmt.Sprintf("  -%s, --%s", f.Shorthand, f.Name)
-			} else {
-				line = fmt.Sprintf("      --%s", f.Name)
-			}
 
-			if f.Usage != "" {
-				line = line + ": " + f.Usage
-			}
+	return flagSet
+}
 
-			_, _ = fmt.Fprint(os.Stderr, line)
-			_, _ = fmt.Fprintf(os.Stderr, "\n")
-		})
-	}
+// registerFlags registers the flags for all fields in the flag set. Flags that were registered by a previous call
+// are reused, so that the same flag set can be used for multiple calls of Collector.Get.
+func (s *FlagsSource) registerFlags(flagSet *pflag.FlagSet, fields []Field) error {
+	s.fieldToFlagInfo = map[string]*flagInfo{}
 
 	for i, f := range fields {
+		// plain structs are configured by their fields' flags
+		if fields[i].IgnoredBy(flagKey) || isContainerStruct(&fields[i]) {
+			continue
+		}
+
 		flagConfig, err := readFlagConfig(f.Configs()[flagKey])
 		if err != nil {
 			return err
 		}
 
-		localField := f
-		name := extractFlagName(&localField)
+		fullname := flagName(&fields[i], s.separator)
 
-		fullname := strings.ToLower(strings.Join(append(f.BaseNames(extractFlagName), name), s.separator))
+		if existing := flagSet.Lookup(fullname); existing != nil {
+			value, ok := existing.Value.(*flagValue)
+			if !ok {
+				return fmt.Errorf("%s: %w", fullname, ErrFlagConflict)
+			}
+
+			s.fieldToFlagInfo[key(&fields[i])] = &flagInfo{value: value, flag: existing}
+
+			continue
+		}
+
+		value, err := newFlagValue(&fields[i], defaultValue(&fields[i]))
+		if err != nil {
+			return err
+		}
+
+		registered := flagSet.VarPF(value, fullname, flagConfig.ShortName, f.Description())
+		registered.NoOptDefVal = value.noOptDefVal()
 
 		s.fieldToFlagInfo[key(&fields[i])] = &flagInfo{
-			valueStr: flagSet.StringP(fullname, flagConfig.ShortName, "", f.Description()),
-			flag:     flagSet.Lookup(fullname),
+			value: value,
+			flag:  registered,
 		}
 	}
 
-	if err := flagSet.Parse(args); err != nil {
-		if errors.Is(err, pflag.ErrHelp) {
-			return ErrHelp
+	s.registerNegatedFlags(flagSet)
+
+	if s.goFlagSet != nil {
+		registerGoFlags(s.goFlagSet, flagSet)
+	}
+
+	return nil
+}
+
+// registerGoFlags registers all flags of the flag set in the standard library's flag set if they are not defined yet.
+// Short names are registered as separate flags with the same value.
+func registerGoFlags(goFlagSet *goflag.FlagSet, flagSet *pflag.FlagSet) {
+	flagSet.VisitAll(func(f *pflag.Flag) {
+		for _, name := range []string{f.Name, f.Shorthand} {
+			if name != "" && goFlagSet.Lookup(name) == nil {
+				goFlagSet.Var(goFlagValue{f.Value}, name, f.Usage)
+			}
 		}
+	})
+}
 
-		return err
+// parseGoFlags parses the standard library's flag set if it isn't parsed already.
+func (s *FlagsSource) parseGoFlags(fields []Field, args []string) error {
+	s.indexedFlags = map[string]*pflag.Flag{}
+
+	if !s.goFlagSet.Parsed() {
+		if err := s.goFlagSet.Parse(args); err != nil {
+			if errors.Is(err, goflag.ErrHelp) {
+				return &HelpError{Usage: s.renderHelp(fields)}
+			}
+
+			return err
+		}
 	}
 
+	s.remaining = s.goFlagSet.Args()
+
 	return nil
 }
 
+// registerNegatedFlags registers hidden --no-<name> flags for all boolean flags to set them to false.
+// They are registered after all other flags so that they don't conflict with the flags of fields.
+func (s *FlagsSource) registerNegatedFlags(flagSet *pflag.FlagSet) {
+	for _, info := range s.fieldToFlagInfo {
+		name := negationPrefix + info.flag.Name
+		if info.value.kind != boolFlag || flagSet.Lookup(name) != nil {
+			continue
+		}
+
+		negated := flagSet.VarPF(&negatedFlagValue{target: info.value}, name, "", "")
+		negated.NoOptDefVal = "true"
+		negated.Hidden = true
+	}
+}
+
+// registerIndexedFlags registers hidden flags for all indexed flags of slices like --servers.0.host in the arguments,
+// since they can't be known before.
+func (s *FlagsSource) registerIndexedFlags(flagSet *pflag.FlagSet, fields []Field, args []string) {
+	s.indexedFlags = map[string]*pflag.Flag{}
+
+	var indexedPrefixes []string
+
+	for i := range fields {
+		if isIndexable(fields[i].value) && !fields[i].IgnoredBy(flagKey) {
+			indexedPrefixes = append(indexedPrefixes, flagName(&fields[i], s.separator)+s.separator)
+		}
+	}
+
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if !strings.HasPrefix(arg, "--") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !isIndexedName(name, indexedPrefixes) || flagSet.Lookup(name) != nil {
+			continue
+		}
+
+		flagSet.String(name, "", "")
+		indexedFlag := flagSet.Lookup(name)
+		indexedFlag.Hidden = true
+		s.indexedFlags[name] = indexedFlag
+	}
+}
+
+// defaultValue returns the default value defined in the field's struct tag or the string representation of the field's
+// current value to be shown as the default in the flag's usage. Zero values are not shown and secrets are redacted.
+func defaultValue(f *Field) string {
+	def, hasDefault := f.Configs()[defaultKey]
+	if !hasDefault && (!f.value.IsValid() || f.value.IsZero()) {
+		return ""
+	}
+
+	if isSecret(f) {
+		return redacted
+	}
+
+	if hasDefault {
+		return def
+	}
+
+	if f.value.Kind() == reflect.Struct && !f.Type().Implements(textMarshaler) {
+		// plain structs are configured by their fields' flags
+		return ""
+	}
+
+	return toString(f.value)
+}
+
+// Locate returns the long name of the command line flag that is looked up for a certain field.
+func (s *FlagsSource) Locate(field *Field) string {
+	if field.IgnoredBy(flagKey) {
+		return ""
+	}
+
+	return "--" + flagName(field, s.separator)
+}
+
+// flagName returns the distinct long flag name for a field without the leading dashes.
+func flagName(f *Field, separator string) string {
+	return strings.ToLower(strings.Join(append(f.BaseNames(extractFlagName), extractFlagName(f)), separator))
+}
+
 func extractFlagName(f *Field) string {
 	// ignored on this case since the error will be checked in other iterations
 	// the fields flagConfigs could be cached to improve performance
@@ -1,8 +1,13 @@
 package alligotor
 
 import (
+	goflag "flag"
+	"reflect"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
 )
 
 var _ = Describe("flags", func() {
@@ -62,7 +67,7 @@ var _ = Describe("flags", func() {
 
 				flagInfo, ok := s.fieldToFlagInfo[key(&fields[0])]
 				Expect(ok).To(BeTrue())
-				Expect(*flagInfo.valueStr).To(Equal("3000"))
+				Expect(flagInfo.value.String()).To(Equal("3000"))
 			})
 			It("supports overwriting longname", func() {
 				fields[0].configs = map[string]string{flagKey: "overwrite"}
@@ -70,7 +75,7 @@ var _ = Describe("flags", func() {
 
 				flagInfo, ok := s.fieldToFlagInfo[key(&fields[0])]
 				Expect(ok).To(BeTrue())
-				Expect(*flagInfo.valueStr).To(Equal("4000"))
+				Expect(flagInfo.value.String()).To(Equal("4000"))
 			})
 			It("returns ErrHelp if --help is specified", func() {
 				err := s.initFlagMap(nil, []string{"--help"})
@@ -138,5 +143,179 @@ var _ = Describe("flags", func() {
 				})
 			})
 		})
+		Describe("Locate", func() {
+			It("returns the long flag name", func() {
+				fields[0].base = []Field{{name: "Base"}}
+				Expect(s.Locate(&fields[0])).To(Equal("--base" + separator + name))
+			})
+		})
+	})
+	Describe("typed flags", func() {
+		type testConfig struct {
+			Enabled   bool
+			Debug     bool `config:"default=true"`
+			Verbosity int  `config:"count=true,flag=v verbosity"`
+			Tags      []string
+			Labels    map[string]int
+			Timeout   time.Duration
+			Password  Secret[int]
+		}
+
+		var (
+			cfg    testConfig
+			fields []Field
+		)
+
+		BeforeEach(func() {
+			cfg = testConfig{}
+
+			var err error
+			fields, err = getFieldsConfigsFromValue(reflect.ValueOf(&cfg).Elem(), nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		readAll := func(args ...string) error {
+			s := NewFlagsSource()
+			if err := s.initFlagMap(fields, args); err != nil {
+				return err
+			}
+
+			for i := range fields {
+				val, err := s.Read(&fields[i])
+				if err != nil {
+					return err
+				}
+
+				if err := setSeparated(fields[i].value, val, fieldSeparators(&fields[i])); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		It("supports boolean flags without values and their negation", func() {
+			cfg.Debug = true
+			Expect(readAll("--enabled", "--no-debug")).To(Succeed())
+			Expect(cfg.Enabled).To(BeTrue())
+			Expect(cfg.Debug).To(BeFalse())
+		})
+		It("supports count flags", func() {
+			Expect(readAll("-vvv")).To(Succeed())
+			Expect(cfg.Verbosity).To(Equal(3))
+		})
+		It("collects repeated slice and map flags", func() {
+			Expect(readAll("--tags", "a", "--tags", "b,c", "--labels", "a=1", "--labels", "b=2")).To(Succeed())
+			Expect(cfg.Tags).To(Equal([]string{"a", "b", "c"}))
+			Expect(cfg.Labels).To(Equal(map[string]int{"a": 1, "b": 2}))
+		})
+		It("validates values while parsing", func() {
+			Expect(readAll("--timeout", "abc")).To(MatchError(ContainSubstring("--timeout")))
+		})
+		It("doesn't include secrets in parse errors", func() {
+			Expect(readAll("--password", "abc")).ToNot(MatchError(ContainSubstring("abc")))
+		})
+		It("uses the Go types as flag types", func() {
+			s := NewFlagsSource()
+			Expect(s.initFlagMap(fields, nil)).To(Succeed())
+
+			types := map[string]string{}
+			for _, info := range s.fieldToFlagInfo {
+				types[info.flag.Name] = info.value.Type()
+			}
+
+			Expect(types).To(Equal(map[string]string{
+				"enabled":   "bool",
+				"debug":     "bool",
+				"verbosity": "int",
+				"tags":      "[]string",
+				"labels":    "map[string]int",
+				"timeout":   "time.Duration",
+				"password":  "int",
+			}))
+		})
+		It("doesn't register flags for nested structs", func() {
+			cfg := struct {
+				DB struct {
+					Host string
+				}
+				Started time.Time
+			}{}
+
+			fields, err := getFieldsConfigsFromValue(reflect.ValueOf(&cfg).Elem(), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			s := NewFlagsSource()
+			Expect(s.initFlagMap(fields, nil)).To(Succeed())
+
+			var names []string
+			for _, info := range s.fieldToFlagInfo {
+				names = append(names, info.flag.Name)
+			}
+
+			Expect(names).To(ConsistOf("db.host", "started"))
+		})
+		It("returns an error for count flags on other types", func() {
+			cfg := struct {
+				Name string `config:"count=true"`
+			}{}
+
+			fields, err := getFieldsConfigsFromValue(reflect.ValueOf(&cfg).Elem(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(NewFlagsSource().initFlagMap(fields, nil)).To(MatchError(ErrInvalidCountFlag))
+		})
+	})
+	Describe("flag sets", func() {
+		type testConfig struct {
+			Port    int
+			Enabled bool `config:"flag=e enabled"`
+		}
+
+		It("registers flags in an existing pflag.FlagSet and parses it", func() {
+			flagSet := pflag.NewFlagSet("app", pflag.ContinueOnError)
+			verbose := flagSet.Bool("verbose", false, "")
+
+			source := NewFlagsSource(WithFlagSet(flagSet), WithArgs([]string{"--port", "1", "--verbose", "-e", "pos"}))
+
+			cfg := testConfig{}
+			Expect(New(source).Get(&cfg)).To(Succeed())
+			Expect(cfg).To(Equal(testConfig{Port: 1, Enabled: true}))
+			Expect(*verbose).To(BeTrue())
+			Expect(source.Args()).To(Equal([]string{"pos"}))
+
+			By("reusing the flags for further calls")
+			Expect(New(source).Get(&cfg)).To(Succeed())
+		})
+		It("uses flag sets that were already parsed by the application", func() {
+			flagSet := pflag.NewFlagSet("app", pflag.ContinueOnError)
+			source := NewFlagsSource(WithFlagSet(flagSet))
+
+			cfg := testConfig{}
+			Expect(source.RegisterFlags(&cfg)).To(Succeed())
+			Expect(flagSet.Parse([]string{"--port", "2", "pos"})).To(Succeed())
+
+			Expect(New(source).Get(&cfg)).To(Succeed())
+			Expect(cfg.Port).To(Equal(2))
+			Expect(source.Args()).To(Equal([]string{"pos"}))
+		})
+		It("returns an error if the application already defines a flag", func() {
+			flagSet := pflag.NewFlagSet("app", pflag.ContinueOnError)
+			flagSet.Int("port", 0, "")
+
+			Expect(New(NewFlagsSource(WithFlagSet(flagSet))).Get(&testConfig{})).To(MatchError(ErrFlagConflict))
+		})
+		It("returns an error for RegisterFlags without a flag set", func() {
+			Expect(NewFlagsSource().RegisterFlags(&testConfig{})).To(MatchError(ErrNoFlagSet))
+		})
+		It("registers flags in a flag.FlagSet", func() {
+			flagSet := goflag.NewFlagSet("app", goflag.ContinueOnError)
+			source := NewFlagsSource(WithGoFlagSet(flagSet), WithArgs([]string{"-port", "3", "-e", "pos"}))
+
+			cfg := testConfig{}
+			Expect(New(source).Get(&cfg)).To(Succeed())
+			Expect(cfg).To(Equal(testConfig{Port: 3, Enabled: true}))
+			Expect(source.Args()).To(Equal([]string{"pos"}))
+			Expect(flagSet.Lookup("no-enabled")).ToNot(BeNil())
+		})
 	})
 })
@@ -0,0 +1,317 @@
+package alligotor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownFormat = errors.New("unknown file format")
+
+// Decoder decodes the data of a config file into a map. Nested objects should be decoded as map[string]interface{}
+// to support looking up nested fields.
+type Decoder func(data []byte) (map[string]interface{}, error)
+
+type format struct {
+	name       string
+	extensions []string
+	decoder    Decoder
+	// positions optionally returns the position of each key in the data to be used in error messages.
+	positions func(data []byte) map[string]position
+}
+
+// position is a position in a file. Line and Column start at 1, 0 means unknown.
+type position struct {
+	Line   int
+	Column int
+}
+
+// formatRegistry holds all known file formats in the order they were registered.
+type formatRegistry struct {
+	mu      sync.RWMutex
+	formats []format
+}
+
+//nolint:gochecknoglobals // registry just like in image package
+var formats = &formatRegistry{
+	formats: []format{
+		{name: "yaml", extensions: []string{".yml", ".yaml"}, decoder: decodeYAML, positions: yamlPositions},
+		{name: "json", extensions: []string{".json"}, decoder: decodeJSON, positions: jsonPositions},
+		{name: "toml", extensions: []string{".toml"}, decoder: decodeTOML},
+	},
+}
+
+// RegisterFormat registers a file format to be used by the ReadersSource and FilesSource.
+// extensions contains the file extensions including the leading dot (e.g. ".yml") that are used to select the format
+// for files. Registering a format with an existing name replaces it.
+//
+// The formats yaml, json and toml are registered by default.
+// For readers without a known extension all formats are tried in the order they were registered.
+func RegisterFormat(name string, extensions []string, decoder Decoder) {
+	formats.mu.Lock()
+	defer formats.mu.Unlock()
+
+	newFormat := format{name: name, extensions: extensions, decoder: decoder}
+
+	for i := range formats.formats {
+		if formats.formats[i].name == name {
+			formats.formats[i] = newFormat
+			return
+		}
+	}
+
+	formats.formats = append(formats.formats, newFormat)
+}
+
+// byName returns the format with the given name.
+func (r *formatRegistry) byName(name string) (format, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, f := range r.formats {
+		if f.name == name {
+			return f, true
+		}
+	}
+
+	return format{}, false
+}
+
+// byExtension returns the format that is registered for the extension of the given path.
+func (r *formatRegistry) byExtension(path string) (format, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ext := strings.ToLower(filepath.Ext(path))
+
+	for _, f := range r.formats {
+		for _, fExt := range f.extensions {
+			if strings.EqualFold(fExt, ext) {
+				return f, true
+			}
+		}
+	}
+
+	return format{}, false
+}
+
+// all returns a copy of all registered formats.
+func (r *formatRegistry) all() []format {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]format(nil), r.formats...)
+}
+
+// formatReader attaches an explicit format to a reader.
+type formatReader struct {
+	io.Reader
+	format string
+}
+
+// NewFormatReader tags a reader with an explicit format name like "yaml" to be used by the ReadersSource instead of
+// detecting the format from the content.
+func NewFormatReader(r io.Reader, format string) io.Reader {
+	return &formatReader{Reader: r, format: format}
+}
+
+// Name returns the name of the wrapped reader if it has one.
+func (r *formatReader) Name() string {
+	if named, ok := r.Reader.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+
+	return ""
+}
+
+// Close closes the wrapped reader if it implements io.Closer.
+func (r *formatReader) Close() error {
+	if closer, ok := r.Reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func decodeYAML(data []byte) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func decodeJSON(data []byte) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func decodeTOML(data []byte) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if err := toml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// yamlPositions returns the positions of all values in a yaml document by their lowercase key path joined by ".".
+func yamlPositions(data []byte) map[string]position {
+	positions := map[string]position{}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return positions
+	}
+
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := joinKeyPath(path, key.Value)
+			positions[keyPath] = position{Line: value.Line, Column: value.Column}
+
+			walk(value, keyPath)
+		}
+	}
+
+	walk(root.Content[0], "")
+
+	return positions
+}
+
+// jsonPositions returns the positions of all values in a json document by their lowercase key path joined by ".".
+func jsonPositions(data []byte) map[string]position {
+	positions := map[string]position{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	// valueStart skips separators after the decoder's current offset to find the start of the next value
+	valueStart := func() int {
+		offset := int(decoder.InputOffset())
+		for offset < len(data) && strings.ContainsRune(" \t\r\n:,", rune(data[offset])) {
+			offset++
+		}
+
+		return offset
+	}
+
+	var walk func(path string) bool
+	walk = func(path string) bool {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+
+		if token != json.Delim('{') {
+			if token == json.Delim('[') {
+				for decoder.More() {
+					if !walk(path + ".[]") {
+						return false
+					}
+				}
+
+				_, err := decoder.Token()
+
+				return err == nil
+			}
+
+			return true
+		}
+
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return false
+			}
+
+			keyPath := joinKeyPath(path, fmt.Sprint(key))
+			positions[keyPath] = lineColumn(data, valueStart())
+
+			if !walk(keyPath) {
+				return false
+			}
+		}
+
+		_, err = decoder.Token()
+
+		return err == nil
+	}
+
+	walk("")
+
+	return positions
+}
+
+func joinKeyPath(path, key string) string {
+	if path == "" {
+		return strings.ToLower(key)
+	}
+
+	return path + "." + strings.ToLower(key)
+}
+
+// lineColumn converts an offset in the data to a position.
+func lineColumn(data []byte, offset int) position {
+	if offset > len(data) {
+		offset = len(data)
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	before := data[:offset]
+	lastNewline := bytes.LastIndexByte(before, '\n')
+
+	return position{Line: bytes.Count(before, []byte("\n")) + 1, Column: offset - lastNewline}
+}
+
+//nolint:gochecknoglobals // compiled regex
+var lineRegex = regexp.MustCompile(`line (\d+)`)
+
+// errorPosition tries to find the position of a decoding error in the data.
+func errorPosition(err error, data []byte) position {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		tomlErr   *toml.DecodeError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		// the offset points after the invalid character
+		return lineColumn(data, int(syntaxErr.Offset)-1)
+	case errors.As(err, &typeErr):
+		return lineColumn(data, int(typeErr.Offset))
+	case errors.As(err, &tomlErr):
+		line, column := tomlErr.Position()
+		return position{Line: line, Column: column}
+	}
+
+	// yaml errors only contain the line in the message
+	if match := lineRegex.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return position{Line: line}
+	}
+
+	return position{}
+}
@@ -0,0 +1,180 @@
+package alligotor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// helpPadding is the number of spaces between the flags' names and their descriptions.
+const helpPadding = 2
+
+// HelpError is returned by Collector.Get if the help was requested with --help or -h.
+// It contains the rendered help, so that applications can show it in their own way.
+// It can be checked for with errors.Is(err, ErrHelp).
+type HelpError struct {
+	Usage string
+}
+
+func (e *HelpError) Error() string {
+	return ErrHelp.Error()
+}
+
+// Unwrap makes it possible to check for ErrHelp using errors.Is.
+func (e *HelpError) Unwrap() error {
+	return ErrHelp
+}
+
+// WithUsageWriter sets the writer the help is written to if it's requested with --help or -h or the arguments can't
+// be parsed. By default, it is written to os.Stderr.
+//
+// The help is only written for the flag set that is created by the FlagsSource. Flag sets given with WithFlagSet or
+// WithGoFlagSet also contain the application's own flags, so their Usage function is kept. If such a flag set is parsed
+// in Collector.Get, the returned HelpError still contains the help for the config's flags.
+func WithUsageWriter(w io.Writer) FlagOption {
+	return func(source *FlagsSource) {
+		source.usageWriter = w
+	}
+}
+
+// WithEnvHints shows the names of the environment variables of the given EnvSource next to the flags in the help,
+// e.g. (env APP_PORT). It's usually the EnvSource that is also passed to the Collector.
+func WithEnvHints(env *EnvSource) FlagOption {
+	return func(source *FlagsSource) {
+		source.envHint = env
+	}
+}
+
+// helpGroup contains the flags for the fields of a nested struct.
+type helpGroup struct {
+	name        string
+	description string
+	lines       []string
+}
+
+// writeUsage writes the help for the fields to the configured usage writer.
+func (s *FlagsSource) writeUsage(fields []Field) {
+	w := s.usageWriter
+	if w == nil {
+		w = os.Stderr
+	}
+
+	_, _ = io.WriteString(w, s.renderHelp(fields))
+}
+
+// renderHelp renders the help for the fields' flags grouped by their nested structs. Every flag is shown with its
+// type, description, default value, the environment variable of the linked EnvSource and whether it's required.
+func (s *FlagsSource) renderHelp(fields []Field) string {
+	var buf bytes.Buffer
+
+	_, _ = fmt.Fprintf(&buf, "Usage of %s:\n", os.Args[0])
+
+	for _, group := range s.helpGroups(fields) {
+		header := group.name
+		if header == "" {
+			header = "Flags"
+		}
+
+		if group.description != "" {
+			header += " - " + group.description
+		}
+
+		_, _ = fmt.Fprintf(&buf, "\n%s:\n", header)
+
+		tw := tabwriter.NewWriter(&buf, 0, 0, helpPadding, ' ', 0)
+
+		for _, line := range group.lines {
+			_, _ = fmt.Fprintln(tw, line)
+		}
+
+		_ = tw.Flush()
+	}
+
+	return buf.String()
+}
+
+// helpGroups returns the lines for all visible flags grouped by the struct that contains their fields
+// in the order of the fields.
+func (s *FlagsSource) helpGroups(fields []Field) []*helpGroup {
+	var (
+		groups []*helpGroup
+		byName = map[string]*helpGroup{}
+	)
+
+	for i := range fields {
+		f := &fields[i]
+
+		info, ok := s.fieldToFlagInfo[key(f)]
+		if !ok || info.flag.Hidden {
+			continue
+		}
+
+		groupName := ""
+		if len(f.Base()) > 0 {
+			parent := f.Base()[len(f.Base())-1]
+			groupName = fieldPath(&parent)
+		}
+
+		group, ok := byName[groupName]
+		if !ok {
+			group = &helpGroup{name: groupName}
+			if len(f.Base()) > 0 {
+				group.description = f.Base()[len(f.Base())-1].Description()
+			}
+
+			byName[groupName] = group
+			groups = append(groups, group)
+		}
+
+		group.lines = append(group.lines, s.helpLine(f, info))
+	}
+
+	return groups
+}
+
+// helpLine renders the line for a single flag separated into the flag's names and its description by a tab.
+func (s *FlagsSource) helpLine(f *Field, info *flagInfo) string {
+	names := "      --" + info.flag.Name
+	if info.flag.Shorthand != "" {
+		names = fmt.Sprintf("  -%s, --%s", info.flag.Shorthand, info.flag.Name)
+	}
+
+	//nolint:exhaustive // only bool and count flags don't need a value
+	switch info.value.kind {
+	case boolFlag:
+		names += ", --" + negationPrefix + info.flag.Name
+	case countFlag:
+	default:
+		names += " " + info.value.Type()
+	}
+
+	var hints []string
+
+	if info.flag.DefValue != "" {
+		hints = append(hints, "default "+info.flag.DefValue)
+	}
+
+	if s.envHint != nil && !f.IgnoredBy(envKey) {
+		hints = append(hints, "env "+envName(f, s.envHint.prefix, s.envHint.separator))
+	}
+
+	if f.Configs()[requiredKey] == "true" {
+		hints = append(hints, "required")
+	}
+
+	description := f.Description()
+	if len(hints) > 0 {
+		description = strings.TrimSpace(description + " (" + strings.Join(hints, ", ") + ")")
+	}
+
+	return names + "\t" + description
+}
+
+// isContainerStruct checks if the field is a plain struct that is configured by the flags of its fields.
+func isContainerStruct(f *Field) bool {
+	return f.value.IsValid() && f.value.Kind() == reflect.Struct && !isValueStruct(f.value)
+}
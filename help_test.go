@@ -0,0 +1,84 @@
+package alligotor
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+)
+
+var _ = Describe("help", func() {
+	type db struct {
+		Host     string         `description:"database host" config:"required=true"`
+		Password Secret[string] `config:"default=changeme"`
+	}
+
+	type testConfig struct {
+		Port    int           `description:"port to listen on" config:"flag=p port"`
+		Timeout time.Duration `config:"default=5s,env=-"`
+		Debug   bool
+		Verbose int `config:"count=true,flag=v verbose"`
+		DB      db  `description:"database settings"`
+	}
+
+	It("renders the help grouped by nested structs", func() {
+		var buf bytes.Buffer
+
+		args := os.Args
+		DeferCleanup(func() { os.Args = args })
+		os.Args = []string{"app"}
+
+		env := NewEnvSource("app")
+		err := New(
+			env,
+			NewFlagsSource(WithArgs([]string{"--help"}), WithUsageWriter(&buf), WithEnvHints(env)),
+		).Get(&testConfig{})
+		Expect(err).To(MatchError(ErrHelp))
+
+		var helpErr *HelpError
+		Expect(errors.As(err, &helpErr)).To(BeTrue())
+		Expect(helpErr.Usage).To(Equal(`Usage of app:
+
+Flags:
+  -p, --port int               port to listen on (env APP_PORT)
+      --timeout time.Duration  (default 5s)
+      --debug, --no-debug      (env APP_DEBUG)
+  -v, --verbose                (env APP_VERBOSE)
+
+DB - database settings:
+      --db.host string      database host (env APP_DB_HOST, required)
+      --db.password string  (default ******, env APP_DB_PASSWORD)
+`))
+	})
+	It("writes the help to the usage writer", func() {
+		var buf bytes.Buffer
+
+		err := New(NewFlagsSource(WithArgs([]string{"-h"}), WithUsageWriter(&buf))).Get(&testConfig{})
+		Expect(err).To(MatchError(ErrHelp))
+		Expect(buf.String()).To(Equal(err.(*HelpError).Usage))
+		Expect(buf.String()).ToNot(ContainSubstring("env"))
+	})
+	It("doesn't show env hints without WithEnvHints", func() {
+		err := New(NewEnvSource("app"), NewFlagsSource(WithArgs([]string{"-h"}), WithUsageWriter(io.Discard))).
+			Get(&testConfig{})
+		Expect(err).To(MatchError(ErrHelp))
+		Expect(err.(*HelpError).Usage).ToNot(ContainSubstring("APP_"))
+	})
+	It("keeps the usage of given flag sets but returns the help for the config's flags", func() {
+		var buf bytes.Buffer
+
+		flagSet := pflag.NewFlagSet("app", pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.Usage = func() { buf.WriteString("custom usage") }
+
+		err := New(NewFlagsSource(WithFlagSet(flagSet), WithArgs([]string{"-h"}))).Get(&testConfig{})
+		Expect(err).To(MatchError(ErrHelp))
+		Expect(buf.String()).To(Equal("custom usage"))
+		Expect(err.(*HelpError).Usage).To(ContainSubstring("--db.host string"))
+	})
+})
@@ -0,0 +1,34 @@
+package alligotor
+
+import (
+	"reflect"
+
+	. "github.com/onsi/gomega"
+)
+
+// pathSource returns the values for the fields by their path in the config struct.
+type pathSource map[string]interface{}
+
+func (s pathSource) Read(field *Field) (interface{}, error) {
+	return s[fieldPath(field)], nil
+}
+
+// structFields returns the fields of the struct v points to.
+func structFields(v interface{}) []Field {
+	fields, err := getFieldsConfigsFromValue(reflect.ValueOf(v).Elem(), nil)
+	Expect(err).ToNot(HaveOccurred())
+
+	return fields
+}
+
+// fieldPaths returns the paths of the fields of the struct v points to.
+func fieldPaths(v interface{}) []string {
+	fields := structFields(v)
+
+	paths := make([]string, 0, len(fields))
+	for i := range fields {
+		paths = append(paths, fieldPath(&fields[i]))
+	}
+
+	return paths
+}
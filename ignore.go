@@ -0,0 +1,57 @@
+package alligotor
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	// ignoreValue skips a field entirely if used as `config:"-"` or for a single source if used as `config:"env=-"`.
+	ignoreValue = "-"
+	sourcesKey  = "sources"
+)
+
+// isIgnoredKind checks if fields of the kind can't be configured and should be skipped automatically.
+func isIgnoredKind(kind reflect.Kind) bool {
+	//nolint:exhaustive // all other kinds can be configured
+	switch kind {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	}
+
+	return false
+}
+
+// IgnoredBy checks if the field should not be read by the source with the given name like "env", "flag" or "file".
+// A field is ignored by a source if it or any of its parents has the source's key set to "-", e.g.
+// `config:"flag=-"`, or if they limit the sources with `config:"sources=file,env"` without including the source.
+// Custom sources can use this to support the same struct tags.
+func (f *Field) IgnoredBy(source string) bool {
+	fields := append(append([]Field(nil), f.Base()...), *f)
+
+	for _, field := range fields {
+		configs := field.Configs()
+		if configs[source] == ignoreValue {
+			return true
+		}
+
+		sources, ok := configs[sourcesKey]
+		if !ok {
+			continue
+		}
+
+		included := false
+
+		for _, s := range strings.Split(sources, ",") {
+			if strings.TrimSpace(s) == source {
+				included = true
+			}
+		}
+
+		if !included {
+			return true
+		}
+	}
+
+	return false
+}
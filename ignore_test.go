@@ -0,0 +1,118 @@
+package alligotor
+
+import (
+	"bytes"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ignore", func() {
+	type db struct {
+		Host     string
+		Password string `config:"sources=file,env"`
+	}
+
+	type testConfig struct {
+		Name    string
+		Skipped string `config:"-"`
+		NoEnv   string `config:"env=-"`
+		NoFlag  string `config:"flag=-"`
+		NoFile  string `config:"file=-"`
+		DB      db     `config:"flag=-"`
+		Done    chan struct{}
+		Handler func()
+		Mu      sync.Mutex
+		hidden  string
+	}
+
+	It("skips ignored, unexported and unconfigurable fields", func() {
+		Expect(fieldPaths(&testConfig{})).To(Equal([]string{
+			"Name", "NoEnv", "NoFlag", "NoFile", "DB", "DB.Host", "DB.Password", "Mu",
+		}))
+	})
+	It("joins segments without = to the previous value", func() {
+		Expect(readParameterConfig("sources=file,env,required=true")).To(Equal(map[string]string{
+			"sources":  "file,env",
+			"required": "true",
+		}))
+	})
+	It("returns an error for segments without = after other keys", func() {
+		_, err := readParameterConfig("env=a,b,required=true")
+		Expect(err).To(MatchError(ErrMalformedConfigTag))
+	})
+	Describe("IgnoredBy", func() {
+		It("checks the field and its parents", func() {
+			fields := structFields(&testConfig{})
+
+			ignored := map[string][]string{}
+			for i := range fields {
+				for _, source := range []string{envKey, flagKey, fileKey} {
+					if fields[i].IgnoredBy(source) {
+						ignored[fieldPath(&fields[i])] = append(ignored[fieldPath(&fields[i])], source)
+					}
+				}
+			}
+
+			Expect(ignored).To(Equal(map[string][]string{
+				"NoEnv":       {envKey},
+				"NoFlag":      {flagKey},
+				"NoFile":      {fileKey},
+				"DB":          {flagKey},
+				"DB.Host":     {flagKey},
+				"DB.Password": {flagKey},
+			}))
+		})
+	})
+	It("doesn't read ignored fields from the sources", func() {
+		for name, val := range map[string]string{"APP_NOENV": "env", "APP_NOFILE": "env", "APP_SKIPPED": "env"} {
+			Expect(os.Setenv(name, val)).To(Succeed())
+			DeferCleanup(os.Unsetenv, name)
+		}
+
+		args := os.Args
+		DeferCleanup(func() { os.Args = args })
+		os.Args = []string{"cmd", "--noenv", "flag"}
+
+		cfg := testConfig{hidden: "kept"}
+		c := New(
+			NewReadersSource(bytes.NewReader([]byte(`{"noFile": "file", "db": {"password": "file"}}`))),
+			NewEnvSource("app"),
+			NewFlagsSource(),
+		)
+		Expect(c.Get(&cfg)).To(Succeed())
+		Expect(cfg.Skipped).To(BeEmpty())
+		Expect(cfg.hidden).To(Equal("kept"))
+		Expect(cfg.NoEnv).To(Equal("flag"))
+		Expect(cfg.NoFile).To(Equal("env"))
+		Expect(cfg.DB.Password).To(Equal("file"))
+	})
+	It("doesn't dump ignored fields in file formats", func() {
+		cfg := testConfig{Name: "name", NoFile: "nofile", DB: db{Host: "host", Password: "password"}}
+		c := New(NewReadersSource(), NewEnvSource("app"))
+
+		out, err := c.Dump(&cfg, DumpYAML)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).ToNot(ContainSubstring("nofile"))
+		Expect(string(out)).ToNot(ContainSubstring("'-'"))
+		Expect(string(out)).To(ContainSubstring("Password: password"))
+
+		type envOnly struct {
+			Token string `config:"sources=env"`
+		}
+
+		out, err = c.Dump(&envOnly{Token: "token"}, DumpJSON)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).ToNot(ContainSubstring("token"))
+
+		out, err = c.Dump(&envOnly{Token: "token"}, DumpEnv)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(ContainSubstring("APP_TOKEN=token"))
+	})
+	It("doesn't register flags for ignored fields", func() {
+		s := NewFlagsSource()
+		Expect(s.initFlagMap(structFields(&testConfig{}), []string{"--db.password", "secret"})).ToNot(Succeed())
+	})
+})
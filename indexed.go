@@ -0,0 +1,149 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	mergeKey = "merge"
+	// maxSliceIndex limits the length of slices built from indexed keys to prevent huge allocations caused by typos.
+	maxSliceIndex = 10000
+)
+
+var ErrInvalidIndex = errors.New("invalid slice index")
+
+// indexedKeys reads slices from keys containing the index of the element, like APP_SERVERS_0_HOST for environment
+// variables or --servers.0.host for flags. For slices of structs the element's fields are appended to the index,
+// for other slices the key with the index contains the element's value.
+type indexedKeys struct {
+	separator string
+	// values contains all keys that are set with their values
+	values map[string]string
+	// relName returns the name of a field relative to its struct element, e.g. HOST, without the separator
+	relName func(f *Field) string
+}
+
+// isIndexable checks if a field's value can be read from indexed keys.
+func isIndexable(value reflect.Value) bool {
+	return value.IsValid() && value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8
+}
+
+// mergeSlices checks if indexed keys should be merged into the existing slice for a field instead of replacing it.
+func mergeSlices(f *Field) bool {
+	return f.Configs()[mergeKey] == "true"
+}
+
+// readSlice builds a slice from the keys below the given prefix.
+// If merge is true, the elements of current are kept and only the values set with indexed keys are overwritten.
+// Otherwise, a new slice is built. If there are no indexed keys, ok is false.
+func (k indexedKeys) readSlice(current reflect.Value, prefix string, merge bool) (slice reflect.Value, ok bool, err error) {
+	indices, err := k.indices(prefix)
+	if err != nil || len(indices) == 0 {
+		return reflect.Value{}, false, err
+	}
+
+	length := indices[len(indices)-1] + 1
+	if merge && current.Len() > length {
+		length = current.Len()
+	}
+
+	slice = reflect.MakeSlice(current.Type(), length, length)
+	if merge {
+		reflect.Copy(slice, current)
+	}
+
+	for _, index := range indices {
+		if err := k.readElem(slice.Index(index), prefix+k.separator+strconv.Itoa(index), merge); err != nil {
+			return reflect.Value{}, false, err
+		}
+	}
+
+	return slice, true, nil
+}
+
+// indices returns all indices that are used in keys below the prefix in ascending order.
+func (k indexedKeys) indices(prefix string) ([]int, error) {
+	found := map[int]bool{}
+
+	for key := range k.values {
+		if !strings.HasPrefix(key, prefix+k.separator) {
+			continue
+		}
+
+		indexStr, _, _ := strings.Cut(strings.TrimPrefix(key, prefix+k.separator), k.separator)
+		if indexStr == "" || strings.Trim(indexStr, "0123456789") != "" {
+			continue
+		}
+
+		index, err := strconv.Atoi(indexStr)
+		if err != nil || index > maxSliceIndex {
+			return nil, fmt.Errorf("%s: %w: maximum is %d", key, ErrInvalidIndex, maxSliceIndex)
+		}
+
+		found[index] = true
+	}
+
+	indices := make([]int, 0, len(found))
+	for index := range found {
+		indices = append(indices, index)
+	}
+
+	sort.Ints(indices)
+
+	return indices, nil
+}
+
+// readElem sets the values for a single element of a slice from the keys below the prefix.
+func (k indexedKeys) readElem(elem reflect.Value, prefix string, merge bool) error {
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct || isValueStruct(elem) {
+		if raw, ok := k.values[prefix]; ok {
+			return set(elem, []byte(raw))
+		}
+
+		return nil
+	}
+
+	fields, err := getFieldsConfigsFromValue(elem, nil)
+	if err != nil {
+		return err
+	}
+
+	for i := range fields {
+		f := &fields[i]
+		key := prefix + k.separator + k.relName(f)
+
+		if raw, ok := k.values[key]; ok {
+			if err := setSeparated(f.value, []byte(raw), fieldSeparators(f)); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+
+		if !isIndexable(f.value) {
+			continue
+		}
+
+		slice, ok, err := k.readSlice(f.value, key, merge || mergeSlices(f))
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			f.value.Set(slice)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package alligotor
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("indexed keys", func() {
+	type server struct {
+		Host  string
+		Port  int
+		Ports []int
+	}
+
+	type testConfig struct {
+		Servers []server
+		Merged  []server `config:"merge=true"`
+		Tags    []int
+		Ptrs    []*server
+	}
+
+	var (
+		cfg    testConfig
+		fields []Field
+	)
+
+	BeforeEach(func() {
+		cfg = testConfig{
+			Servers: []server{{Host: "file0", Port: 1}, {Host: "file1", Port: 2}},
+			Merged:  []server{{Host: "file0", Port: 1}, {Host: "file1", Port: 2}},
+		}
+
+		var err error
+		fields, err = getFieldsConfigsFromValue(reflect.ValueOf(&cfg).Elem(), nil)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	readAll := func(source ConfigSource) {
+		for i := range fields {
+			val, err := source.Read(&fields[i])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(set(fields[i].value, val)).To(Succeed())
+		}
+	}
+
+	Describe("EnvSource", func() {
+		It("builds slices from indexed variables", func() {
+			s := NewEnvSource("app")
+			s.envMap = map[string]string{
+				"APP_SERVERS_0_HOST":    "env0",
+				"APP_SERVERS_1_PORTS_0": "80",
+				"APP_SERVERS_1_PORTS_1": "443",
+				"APP_MERGED_1_HOST":     "env1",
+				"APP_MERGED_2_PORT":     "3",
+				"APP_TAGS_0":            "1",
+				"APP_TAGS_2":            "3",
+				"APP_PTRS_0_HOST":       "ptr",
+			}
+
+			readAll(s)
+			Expect(cfg.Servers).To(Equal([]server{{Host: "env0"}, {Ports: []int{80, 443}}}))
+			Expect(cfg.Merged).To(Equal([]server{{Host: "file0", Port: 1}, {Host: "env1", Port: 2}, {Port: 3}}))
+			Expect(cfg.Tags).To(Equal([]int{1, 0, 3}))
+			Expect(cfg.Ptrs).To(Equal([]*server{{Host: "ptr"}}))
+		})
+		It("keeps the slice if there are no indexed variables", func() {
+			s := NewEnvSource("app")
+			s.envMap = map[string]string{}
+
+			readAll(s)
+			Expect(cfg.Servers).To(Equal([]server{{Host: "file0", Port: 1}, {Host: "file1", Port: 2}}))
+		})
+		It("merges indexed variables into the slice's own variable", func() {
+			s := NewEnvSource("app")
+			s.envMap = map[string]string{
+				"APP_SERVERS":        `[{"Host":"json0"},{"Host":"json1"}]`,
+				"APP_SERVERS_1_PORT": "2",
+			}
+
+			readAll(s)
+			Expect(cfg.Servers).To(Equal([]server{{Host: "json0"}, {Host: "json1", Port: 2}}))
+		})
+		It("returns an error for huge indices", func() {
+			s := NewEnvSource("app")
+			s.envMap = map[string]string{"APP_TAGS_99999999": "1"}
+
+			_, err := s.Read(&fields[2])
+			Expect(err).To(MatchError(ErrInvalidIndex))
+		})
+		It("does not report indexed variables as unknown", func() {
+			Expect(os.Setenv("APP_SERVERS_0_HOST", "env0")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "APP_SERVERS_0_HOST")
+
+			Expect(NewEnvSource("app", WithDisallowUnknownEnv()).Init(fields)).To(Succeed())
+		})
+	})
+	Describe("FlagsSource", func() {
+		It("builds slices from indexed flags", func() {
+			s := NewFlagsSource()
+			Expect(s.initFlagMap(fields, strings.Fields(
+				"--servers.0.host flag0 --servers.1.ports.0=80 --merged.0.port=5 --tags.1 2",
+			))).To(Succeed())
+
+			readAll(s)
+			Expect(cfg.Servers).To(Equal([]server{{Host: "flag0"}, {Ports: []int{80}}}))
+			Expect(cfg.Merged).To(Equal([]server{{Host: "file0", Port: 5}, {Host: "file1", Port: 2}}))
+			Expect(cfg.Tags).To(Equal([]int{0, 2}))
+		})
+		It("returns errors for unknown flags", func() {
+			s := NewFlagsSource()
+			Expect(s.initFlagMap(fields, []string{"--other.0.host=test"})).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,236 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrInterpolation = errors.New("interpolation failed")
+
+// WithInterpolation enables the expansion of variables in string values of the config files.
+// The following forms are supported:
+//   - ${VAR} is replaced by the value of VAR or an empty string if it's not set
+//   - ${VAR:-default} is replaced by the value of VAR or default if VAR is not set or empty
+//   - ${VAR:?message} is replaced by the value of VAR or returns an error containing message if VAR is not set or empty
+//   - $$ is replaced by a single $
+//
+// Variables are looked up in the environment first. If there is no such environment variable, they are resolved as
+// references to other keys in the config files, e.g. ${db.host}. References are resolved recursively with the last
+// file containing the key winning, just like for the fields. Reference cycles result in an error.
+func WithInterpolation() ReadersOption {
+	return func(o *readersOptions) {
+		o.interpolate = true
+	}
+}
+
+// WithEnvInterpolation enables the expansion of variables in the values of environment variables
+// in the forms supported by WithInterpolation. Variables are only looked up in the environment.
+func WithEnvInterpolation() EnvOption {
+	return func(env *EnvSource) {
+		env.interpolate = true
+	}
+}
+
+// varLookup returns the value of a variable and whether it is set.
+type varLookup func(name string) (string, bool, error)
+
+// interpolateFileMaps returns copies of the rawFileMaps with all variables in string values expanded.
+func (s *ReadersSource) interpolateFileMaps() ([]*ciMap, error) {
+	fileMaps := make([]*ciMap, 0, len(s.rawFileMaps))
+
+	for i, m := range s.rawFileMaps {
+		val, err := s.interpolateValue(m.m, "")
+		if err != nil {
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) {
+				parseErr.File = s.names[i]
+
+				if i < len(s.positions) {
+					pos := s.positions[i][parseErr.Key]
+					parseErr.Line, parseErr.Column = pos.Line, pos.Column
+				}
+			}
+
+			return nil, err
+		}
+
+		expanded, _ := val.(map[string]interface{})
+		fileMaps = append(fileMaps, &ciMap{m: expanded})
+	}
+
+	return fileMaps, nil
+}
+
+// interpolateValue returns a copy of val with all variables in strings expanded.
+// Errors are returned as *ParseError containing the key.
+func (s *ReadersSource) interpolateValue(val interface{}, key string) (interface{}, error) {
+	switch typedVal := val.(type) {
+	case string:
+		expanded, err := expandVars(typedVal, s.lookupVar([]string{key}))
+		if err != nil {
+			return nil, &ParseError{Key: key, Err: err}
+		}
+
+		return expanded, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(typedVal))
+
+		for k, v := range typedVal {
+			expanded, err := s.interpolateValue(v, joinKeyPath(key, k))
+			if err != nil {
+				return nil, err
+			}
+
+			m[k] = expanded
+		}
+
+		return m, nil
+	case []interface{}:
+		slice := make([]interface{}, 0, len(typedVal))
+
+		for _, v := range typedVal {
+			expanded, err := s.interpolateValue(v, key)
+			if err != nil {
+				return nil, err
+			}
+
+			slice = append(slice, expanded)
+		}
+
+		return slice, nil
+	}
+
+	return val, nil
+}
+
+// lookupVar returns a varLookup that checks the environment first and then the keys in the rawFileMaps.
+// stack contains the keys that are currently resolved to detect cycles.
+func (s *ReadersSource) lookupVar(stack []string) varLookup {
+	return func(name string) (string, bool, error) {
+		if val, ok := os.LookupEnv(name); ok {
+			return val, true, nil
+		}
+
+		key := strings.ToLower(name)
+
+		for i := len(s.rawFileMaps) - 1; i >= 0; i-- {
+			val, ok := s.rawFileMaps[i].get(strings.Split(key, "."))
+			if !ok {
+				continue
+			}
+
+			for _, resolving := range stack {
+				if resolving == key {
+					cycle := strings.Join(append(stack, key), " -> ")
+					return "", false, fmt.Errorf("%w: reference cycle %s", ErrInterpolation, cycle)
+				}
+			}
+
+			valString, ok := val.(string)
+			if !ok {
+				if !isScalar(val) || val == nil {
+					return "", false, nil
+				}
+
+				return fmt.Sprint(val), true, nil
+			}
+
+			expanded, err := expandVars(valString, s.lookupVar(append(append([]string(nil), stack...), key)))
+
+			return expanded, true, err
+		}
+
+		return "", false, nil
+	}
+}
+
+// expandVars replaces all variables in s as described in WithInterpolation using lookup.
+func expandVars(s string, lookup varLookup) (string, error) {
+	var result strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) || (s[i+1] != '$' && s[i+1] != '{') {
+			result.WriteByte(s[i])
+			continue
+		}
+
+		if s[i+1] == '$' {
+			result.WriteByte('$')
+			i++
+
+			continue
+		}
+
+		end := closingBrace(s, i+2)
+		if end < 0 {
+			result.WriteString(s[i:])
+			break
+		}
+
+		expanded, err := expandVar(s[i+2:end], lookup)
+		if err != nil {
+			return "", err
+		}
+
+		result.WriteString(expanded)
+
+		i = end
+	}
+
+	return result.String(), nil
+}
+
+// expandVar expands a single expression like VAR, VAR:-default or VAR:?message.
+func expandVar(expr string, lookup varLookup) (string, error) {
+	name, arg, op := expr, "", ""
+
+	if index := strings.Index(expr, ":"); index >= 0 && index+1 < len(expr) &&
+		(expr[index+1] == '-' || expr[index+1] == '?') {
+		name, op, arg = expr[:index], expr[index:index+2], expr[index+2:]
+	}
+
+	val, ok, err := lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	if ok && val != "" {
+		return val, nil
+	}
+
+	switch op {
+	case ":-":
+		return expandVars(arg, lookup)
+	case ":?":
+		if arg == "" {
+			arg = "not set"
+		}
+
+		return "", fmt.Errorf("%w: %s: %s", ErrInterpolation, name, arg)
+	}
+
+	return val, nil
+}
+
+// closingBrace returns the index of the brace closing the expression starting at start, taking nested
+// expressions into account. It returns -1 if there is none.
+func closingBrace(s string, start int) int {
+	depth := 0
+
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+
+			depth--
+		}
+	}
+
+	return -1
+}
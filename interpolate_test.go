@@ -0,0 +1,104 @@
+package alligotor
+
+import (
+	"bytes"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("interpolation", func() {
+	Describe("expandVars", func() {
+		vars := map[string]string{"SET": "value", "EMPTY": ""}
+		lookup := func(name string) (string, bool, error) {
+			val, ok := vars[name]
+			return val, ok, nil
+		}
+
+		DescribeTable("expands the supported forms",
+			func(input, expected string) {
+				expanded, err := expandVars(input, lookup)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(expanded).To(Equal(expected))
+			},
+			Entry("plain", "http://${SET}:80", "http://value:80"),
+			Entry("unset", "a${UNSET}b", "ab"),
+			Entry("default for unset", "${UNSET:-default}", "default"),
+			Entry("default for empty", "${EMPTY:-default}", "default"),
+			Entry("no default if set", "${SET:-default}", "value"),
+			Entry("nested default", "${UNSET:-${SET}}", "value"),
+			Entry("escaped", "$${SET} $SET", "${SET} $SET"),
+			Entry("unterminated", "${SET", "${SET"),
+		)
+		It("returns an error for unset required variables", func() {
+			_, err := expandVars("${UNSET:?must be set}", lookup)
+			Expect(err).To(MatchError(ErrInterpolation))
+			Expect(err).To(MatchError("interpolation failed: UNSET: must be set"))
+		})
+	})
+	Describe("ReadersSource", func() {
+		type testConfig struct {
+			DB struct {
+				Host string
+				URL  string
+			}
+			Hosts []string
+		}
+
+		BeforeEach(func() {
+			Expect(os.Setenv("INTERPOLATION_TEST_HOST", "prod.example.com")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "INTERPOLATION_TEST_HOST")
+		})
+		It("expands environment variables and references to other keys", func() {
+			var cfg testConfig
+			c := New(NewReadersSource(
+				bytes.NewReader([]byte("db:\n  host: ${INTERPOLATION_TEST_HOST}\n")),
+				bytes.NewReader([]byte(
+					"db:\n  url: postgres://${db.host}:${DB_PORT:-5432}\nhosts:\n  - ${db.host}\n",
+				)),
+			).WithOptions(WithInterpolation()))
+
+			Expect(c.Get(&cfg)).To(Succeed())
+			Expect(cfg.DB.URL).To(Equal("postgres://prod.example.com:5432"))
+			Expect(cfg.Hosts).To(Equal([]string{"prod.example.com"}))
+		})
+		It("does not expand variables by default", func() {
+			var cfg testConfig
+			c := New(NewReadersSource(bytes.NewReader([]byte("db:\n  host: ${INTERPOLATION_TEST_HOST}\n"))))
+
+			Expect(c.Get(&cfg)).To(Succeed())
+			Expect(cfg.DB.Host).To(Equal("${INTERPOLATION_TEST_HOST}"))
+		})
+		It("returns errors with the file and key", func() {
+			var cfg testConfig
+			c := New(NewReadersSource(
+				newNamedReader(bytes.NewReader([]byte("db:\n  host: ${UNSET_VAR:?required}\n")), "config.yml"),
+			).WithOptions(WithInterpolation()))
+
+			err := c.Get(&cfg)
+			Expect(err).To(MatchError(ErrInterpolation))
+			Expect(err).To(MatchError("config.yml:2:9: db.host: interpolation failed: UNSET_VAR: required"))
+		})
+		It("detects reference cycles", func() {
+			var cfg testConfig
+			c := New(NewReadersSource(
+				bytes.NewReader([]byte("db:\n  host: ${db.url}\n  url: ${db.host}\n")),
+			).WithOptions(WithInterpolation()))
+
+			err := c.Get(&cfg)
+			Expect(err).To(MatchError(ErrInterpolation))
+			Expect(err.Error()).To(ContainSubstring("reference cycle"))
+		})
+	})
+	Describe("EnvSource", func() {
+		It("expands variables if enabled", func() {
+			s := NewEnvSource("app", WithEnvInterpolation())
+			s.envMap = map[string]string{"APP_URL": "http://${HOST}:${PORT:-80}", "HOST": "example.com"}
+
+			val, err := s.Read(&Field{name: "url"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(Equal([]byte("http://example.com:80")))
+		})
+	})
+})
@@ -1,29 +1,98 @@
 package alligotor
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
-	"gopkg.in/yaml.v3"
 )
 
 const fileKey = "file"
 
-var ErrFileFormatNotSupported = errors.New("file format not supported or malformed content")
+var (
+	ErrFileFormatNotSupported = errors.New("file format not supported or malformed content")
+	ErrMalformedFile          = errors.New("malformed config file")
+)
+
+// ParseError describes an error in a config file. It contains the file's name and, if known, the line and column
+// of the error as well as the affected key.
+type ParseError struct {
+	File string
+	// Line and Column start at 1, 0 means unknown.
+	Line   int
+	Column int
+	Key    string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.location(), e.Err)
+}
+
+// location returns the file, position and key of the error in the format "file:line:column: key".
+func (e *ParseError) location() string {
+	location := e.File
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+
+		if e.Column > 0 {
+			location += ":" + strconv.Itoa(e.Column)
+		}
+	}
+
+	if e.Key != "" {
+		location += ": " + e.Key
+	}
+
+	return location
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ReadersOption configures a ReadersSource or FilesSource.
+type ReadersOption func(*readersOptions)
+
+type readersOptions struct {
+	strict              bool
+	disallowUnknownKeys bool
+	interpolate         bool
+}
+
+// WithStrictParsing enables the strict mode.
+// By default, readers whose format can't be detected are skipped and values that don't match the field's type
+// might be silently ignored. In strict mode both result in a *ParseError containing the file name, the position
+// and, for type mismatches, the key and the expected type.
+func WithStrictParsing() ReadersOption {
+	return func(o *readersOptions) {
+		o.strict = true
+	}
+}
 
 // ReadersSource is used to read configuration from any type that implements the io.Reader interface.
-// The data in the readers should be in one of the supported file formats (currently yml and json).
+// The data in the readers should be in one of the registered file formats (by default yml, json and toml,
+// see RegisterFormat). To select a format explicitly instead of detecting it from the content NewFormatReader
+// can be used.
 // This enables a wide range of usages like for example reading the config from an http endpoint or a file.
 //
 // The ReadersSource accepts io.Reader to support as many types as possible. To improve the experience with sources
 // that need to be closed it will also check if the supplied type implements io.Closer and closes the reader
 // if it does.
 type ReadersSource struct {
-	readers  []io.Reader
-	fileMaps []*ciMap
+	readers []io.Reader
+	// rawFileMaps contains the decoded readers, fileMaps the same data after interpolation if it's enabled
+	rawFileMaps []*ciMap
+	fileMaps    []*ciMap
+	// names contains a name for each entry in fileMaps to be able to report where a value was read from.
+	names []string
+	// positions contains the positions of the keys for each entry in fileMaps if the format supports it.
+	positions []map[string]position
+	options   readersOptions
 }
 
 // NewReadersSource returns a new ReadersSource that reads from one or more readers.
@@ -34,21 +103,46 @@ func NewReadersSource(readers ...io.Reader) *ReadersSource {
 	}
 }
 
+// WithOptions applies the given options to the ReadersSource.
+// It returns the ReadersSource to be able to use it inline when defining a Collector's sources.
+func (s *ReadersSource) WithOptions(opts ...ReadersOption) *ReadersSource {
+	for _, opt := range opts {
+		opt(&s.options)
+	}
+
+	return s
+}
+
 // Init initializes the fileMaps property.
 // It should be used right before calling the Read method to load the latest config files' states.
-func (s *ReadersSource) Init(_ []Field) error {
-	for _, reader := range s.readers {
+// Since readers can only be consumed once, they are only read in the first call of Init and the parsed
+// data is kept for subsequent calls.
+// If WithInterpolation is used, the variables in the values are expanded in every call.
+// If WithDisallowUnknownKeys is used, it returns an *UnknownKeysError for keys that don't map onto any of the fields.
+func (s *ReadersSource) Init(fields []Field) error {
+	for i, reader := range s.readers {
 		if err := func() error {
 			if closer, ok := reader.(io.Closer); ok {
 				defer closer.Close()
 			}
 
-			m, err := unmarshal(reader)
-			if err != nil {
+			file, err := decode(reader)
+			if errors.Is(err, ErrFileFormatNotSupported) {
+				if s.options.strict {
+					return &ParseError{File: readerName(reader, i), Err: err}
+				}
+
+				// readers that don't contain any known format are skipped
 				return nil
 			}
 
-			s.fileMaps = append(s.fileMaps, m)
+			if err != nil {
+				return err
+			}
+
+			s.rawFileMaps = append(s.rawFileMaps, file.m)
+			s.names = append(s.names, readerName(reader, i))
+			s.positions = append(s.positions, file.positions)
 
 			return nil
 		}(); err != nil {
@@ -56,6 +150,22 @@ func (s *ReadersSource) Init(_ []Field) error {
 		}
 	}
 
+	s.readers = nil
+	s.fileMaps = s.rawFileMaps
+
+	if s.options.interpolate {
+		fileMaps, err := s.interpolateFileMaps()
+		if err != nil {
+			return err
+		}
+
+		s.fileMaps = fileMaps
+	}
+
+	if s.options.disallowUnknownKeys {
+		return s.checkUnknownKeys(fields)
+	}
+
 	return nil
 }
 
@@ -64,8 +174,14 @@ func (s *ReadersSource) Init(_ []Field) error {
 func (s *ReadersSource) Read(field *Field) (interface{}, error) {
 	var finalVal interface{}
 
-	for _, m := range s.fileMaps {
+	for i, m := range s.fileMaps {
 		val, err := readFileMap(field, m)
+		if s.options.strict {
+			if err := s.checkType(i, field, val, err); err != nil {
+				return nil, err
+			}
+		}
+
 		if err != nil {
 			return nil, err
 		}
@@ -76,25 +192,165 @@ func (s *ReadersSource) Read(field *Field) (interface{}, error) {
 	return finalVal, nil
 }
 
-// unmarshal tries to decode the reader's data into any supported fileType. If it does not work for any file format
-// an ErrFileFormatNotSupported is returned.
+// checkType returns a *ParseError if the value that was read from the fileMap with the given index
+// can't be used for the field.
+func (s *ReadersSource) checkType(index int, field *Field, val interface{}, readErr error) error {
+	raw, ok := s.fileMaps[index].Get(field.BaseNames(extractFileName), extractFileName(field))
+	if !ok || raw == nil || field.IgnoredBy(fileKey) {
+		return nil
+	}
+
+	// nested structs are set by their fields, so they only need to be a section in the file
+	if isContainerStruct(field) && reflect.ValueOf(raw).Kind() == reflect.Map {
+		return nil
+	}
+
+	mismatch := readErr != nil || val == nil
+
+	if valBytes, ok := val.([]byte); ok && field.Type() != reflect.TypeOf(valBytes) {
+		_, err := parseString(reflect.New(field.Type()).Elem(), string(valBytes), fieldSeparators(field))
+		mismatch = err != nil
+	}
+
+	if !mismatch {
+		return nil
+	}
+
+	key := strings.Join(append(field.BaseNames(extractFileName), extractFileName(field)), ".")
+	parseErr := &ParseError{
+		File: s.names[index],
+		Key:  key,
+		// the value is not added to the error since it could be a secret
+		Err: fmt.Errorf("%w: expected %s", ErrTypeMismatch, field.Type()),
+	}
+
+	if index < len(s.positions) {
+		pos := s.positions[index][strings.ToLower(key)]
+		parseErr.Line, parseErr.Column = pos.Line, pos.Column
+	}
+
+	return parseErr
+}
+
+// Locate returns the key that is looked up for a certain field.
+// If any of the readers contains a value for the key, the name of the last one, which is the one that is used
+// by Read, is appended. For files this is the file's path.
+func (s *ReadersSource) Locate(field *Field) string {
+	if field.IgnoredBy(fileKey) {
+		return ""
+	}
+
+	fileKey := strings.Join(append(field.BaseNames(extractFileName), extractFileName(field)), ".")
+
+	for i := len(s.fileMaps) - 1; i >= 0; i-- {
+		if _, ok := s.fileMaps[i].Get(field.BaseNames(extractFileName), extractFileName(field)); ok {
+			return fmt.Sprintf("%s (%s)", fileKey, s.names[i])
+		}
+	}
+
+	return fileKey
+}
+
+// readerName returns the reader's name if it implements a Name method like os.File does.
+// Otherwise, the index of the reader is used to describe it.
+func readerName(r io.Reader, index int) string {
+	if named, ok := r.(interface{ Name() string }); ok && named.Name() != "" {
+		return named.Name()
+	}
+
+	return fmt.Sprintf("reader %d", index)
+}
+
+// unmarshal decodes the reader's data into a ciMap.
+// The format is selected in the following order:
+//  1. the explicit format of readers created with NewFormatReader
+//  2. the format registered for the extension of the reader's name, e.g. for files opened by the FilesSource
+//  3. the first registered format that is able to decode the data
+//
+// If the format is known from 1. or 2. decoding errors are returned.
+// If no format is able to decode the data an ErrFileFormatNotSupported is returned.
 func unmarshal(r io.Reader) (*ciMap, error) {
-	m := newCiMap()
-	if err := yaml.NewDecoder(r).Decode(m); err == nil {
-		return m, nil
+	file, err := decode(r)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := json.NewDecoder(r).Decode(m); err == nil {
-		return m, nil
+	return file.m, nil
+}
+
+// decodedFile is the result of decoding a reader.
+type decodedFile struct {
+	m *ciMap
+	// positions contains the positions of all keys in lowercase joined by ".", if the format supports it.
+	positions map[string]position
+}
+
+// decode decodes the reader's data like described in unmarshal.
+func decode(r io.Reader) (*decodedFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if explicit, ok := r.(*formatReader); ok {
+		f, ok := formats.byName(explicit.format)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", explicit.format, ErrUnknownFormat)
+		}
+
+		return decodeWith(f, data, r)
+	}
+
+	if named, ok := r.(interface{ Name() string }); ok {
+		if f, ok := formats.byExtension(named.Name()); ok {
+			return decodeWith(f, data, r)
+		}
+	}
+
+	for _, f := range formats.all() {
+		if m, err := f.decoder(data); err == nil {
+			return newDecodedFile(f, data, m), nil
+		}
 	}
 
 	return nil, ErrFileFormatNotSupported
 }
 
+// decodeWith decodes the data with the given format. Errors are returned as *ParseError containing the reader's name
+// and the position of the error if it is known.
+func decodeWith(f format, data []byte, r io.Reader) (*decodedFile, error) {
+	m, err := f.decoder(data)
+	if err != nil {
+		pos := errorPosition(err, data)
+
+		return nil, &ParseError{
+			File:   readerName(r, 0),
+			Line:   pos.Line,
+			Column: pos.Column,
+			Err:    fmt.Errorf("%w: %w", ErrMalformedFile, err),
+		}
+	}
+
+	return newDecodedFile(f, data, m), nil
+}
+
+func newDecodedFile(f format, data []byte, m map[string]interface{}) *decodedFile {
+	file := &decodedFile{m: &ciMap{m: m}}
+	if f.positions != nil {
+		file.positions = f.positions(data)
+	}
+
+	return file
+}
+
 // readFileMap reads the value for a given field from the given ciMap.
 // It returns the right type if there is no decoding error otherwise it returns a byte slice that could potentially
 // be decoded later into the target type.
 func readFileMap(f *Field, m *ciMap) (interface{}, error) {
+	if f.IgnoredBy(fileKey) {
+		return nil, nil
+	}
+
 	name := extractFileName(f)
 
 	valueForField, ok := m.Get(f.BaseNames(extractFileName), name)
@@ -105,11 +361,21 @@ func readFileMap(f *Field, m *ciMap) (interface{}, error) {
 	fieldTypeNew := reflect.New(f.Type())
 
 	if f.Type().Kind() == reflect.Struct {
+		// some formats like toml already decode values like timestamps into the right type
+		if reflect.TypeOf(valueForField) == f.Type() {
+			return valueForField, nil
+		}
+
 		// if it's a struct, it could be assigned with TextUnmarshaler, otherwise return nil
 		if valueString, ok := valueForField.(string); ok {
 			return []byte(valueString), nil
 		}
 
+		// secrets can wrap any type, so also numbers or booleans are parsed from their string representation
+		if f.Type().Implements(secretMarkerType) && isScalar(valueForField) {
+			return []byte(fmt.Sprint(valueForField)), nil
+		}
+
 		return nil, nil
 	}
 
@@ -125,6 +391,17 @@ func readFileMap(f *Field, m *ciMap) (interface{}, error) {
 	return fieldTypeNew.Elem().Interface(), nil
 }
 
+// isScalar checks if a value decoded from a file is neither a map nor a slice.
+func isScalar(value interface{}) bool {
+	//nolint:exhaustive // only maps and slices are not scalar
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice:
+		return false
+	}
+
+	return true
+}
+
 func extractFileName(f *Field) string {
 	if f.Configs()[fileKey] != "" {
 		return f.Configs()[fileKey]
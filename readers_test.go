@@ -2,7 +2,9 @@ package alligotor
 
 import (
 	"bytes"
+	"errors"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -33,6 +35,101 @@ test:
 				Expect(jsonMap.m).To(Equal(expectedMap))
 			})
 		})
+		Context("toml", func() {
+			It("should succeed with valid input", func() {
+				tomlBytes := []byte(`[test]
+sub = "lel"
+`)
+				tomlMap, err := unmarshal(bytes.NewReader(tomlBytes))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(tomlMap.m).To(Equal(expectedMap))
+			})
+		})
+		Context("file extension", func() {
+			It("uses the format matching the extension", func() {
+				tomlBytes := []byte(`test = { sub = "lel" }`)
+				tomlMap, err := unmarshal(newNamedReader(bytes.NewReader(tomlBytes), "config.toml"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(tomlMap.m).To(Equal(expectedMap))
+			})
+			It("returns the decoding error if the content does not match the extension", func() {
+				jsonBytes := []byte(`{"test": {"sub": "lel"}}`)
+				_, err := unmarshal(newNamedReader(bytes.NewReader(jsonBytes), "config.toml"))
+				Expect(err).To(MatchError(ErrMalformedFile))
+				Expect(err.Error()).To(HavePrefix("config.toml:1:1: "))
+			})
+		})
+		Context("explicit format", func() {
+			It("uses the given format", func() {
+				yamlBytes := []byte("test:\n  sub: lel\n")
+				yamlMap, err := unmarshal(NewFormatReader(bytes.NewReader(yamlBytes), "yaml"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(yamlMap.m).To(Equal(expectedMap))
+			})
+			It("returns the decoding error", func() {
+				_, err := unmarshal(NewFormatReader(bytes.NewReader([]byte("test: lel")), "json"))
+				Expect(err).To(MatchError(ErrMalformedFile))
+			})
+			It("returns error for unknown formats", func() {
+				_, err := unmarshal(NewFormatReader(bytes.NewReader([]byte("test: lel")), "xml"))
+				Expect(err).To(MatchError(ErrUnknownFormat))
+			})
+		})
+		Context("detection", func() {
+			It("tries the following formats on the whole content", func() {
+				// tabs are not allowed for indentation in yaml
+				jsonBytes := []byte("{\n\t\"test\": {\"sub\": \"lel\"}\n}")
+				jsonMap, err := unmarshal(bytes.NewReader(jsonBytes))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(jsonMap.m).To(Equal(expectedMap))
+			})
+		})
+		Context("registered format", func() {
+			AfterEach(func() {
+				formats.formats = formats.formats[:3]
+			})
+			It("is used for its extensions", func() {
+				RegisterFormat("custom", []string{".custom"}, func(data []byte) (map[string]interface{}, error) {
+					return map[string]interface{}{"test": map[string]interface{}{"sub": string(data)}}, nil
+				})
+
+				customMap, err := unmarshal(newNamedReader(bytes.NewReader([]byte("lel")), "config.custom"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(customMap.m).To(Equal(expectedMap))
+			})
+		})
+		Context("positions", func() {
+			It("returns the line and column of yaml errors", func() {
+				_, err := unmarshal(newNamedReader(bytes.NewReader([]byte("test:\n  sub: lel\n bad")), "config.yml"))
+
+				var parseErr *ParseError
+				Expect(errors.As(err, &parseErr)).To(BeTrue())
+				Expect(parseErr.File).To(Equal("config.yml"))
+				Expect(parseErr.Line).To(Equal(2))
+				Expect(err).To(MatchError(ErrMalformedFile))
+			})
+			It("returns the line and column of json errors", func() {
+				_, err := unmarshal(newNamedReader(bytes.NewReader([]byte("{\n  \"test\": ,\n}")), "config.json"))
+
+				var parseErr *ParseError
+				Expect(errors.As(err, &parseErr)).To(BeTrue())
+				Expect(parseErr.Line).To(Equal(2))
+				Expect(parseErr.Column).To(Equal(11))
+				Expect(err.Error()).To(HavePrefix("config.json:2:11: "))
+			})
+			It("finds the positions of yaml keys", func() {
+				Expect(yamlPositions([]byte("test:\n  Sub: lel\n"))).To(Equal(map[string]position{
+					"test":     {Line: 2, Column: 3},
+					"test.sub": {Line: 2, Column: 8},
+				}))
+			})
+			It("finds the positions of json keys", func() {
+				Expect(jsonPositions([]byte("{\n  \"test\": {\"Sub\": \"lel\"}\n}"))).To(Equal(map[string]position{
+					"test":     {Line: 2, Column: 11},
+					"test.sub": {Line: 2, Column: 19},
+				}))
+			})
+		})
 		Context("not supported", func() {
 			It("should fail with random input", func() {
 				randomBytes := []byte("i don't know what I'm doing here")
@@ -88,6 +185,15 @@ test:
 			Expect(err).ToNot(HaveOccurred())
 			Expect(val).To(Equal(3000))
 		})
+		It("returns values that already have the target type", func() {
+			timestamp := time.Date(2007, 01, 02, 15, 04, 05, 00, time.UTC)
+			field.value = reflect.ValueOf(time.Time{})
+			m.m = map[string]interface{}{name: timestamp}
+
+			val, err := readFileMap(field, m)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(Equal(timestamp))
+		})
 		It("supports arrays", func() {
 			field.value = reflect.ValueOf([]int{})
 			m.m = map[string]interface{}{name: []int{1, 2, 3, 4, 5}}
@@ -150,6 +256,19 @@ test:
 					{m: map[string]interface{}{"test": "1235"}},
 				}))
 			})
+			It("skips readers with unknown content", func() {
+				s = NewReadersSource(bytes.NewReader([]byte("no config")))
+				Expect(s.Init(nil)).To(Succeed())
+				Expect(s.fileMaps).To(BeEmpty())
+			})
+			It("returns errors for malformed readers with a known format", func() {
+				s = NewReadersSource(NewFormatReader(bytes.NewReader([]byte("no config")), "yaml"))
+				Expect(s.Init(nil)).To(MatchError(ErrMalformedFile))
+			})
+			It("returns errors for readers with unknown content in strict mode", func() {
+				s = NewReadersSource(bytes.NewReader([]byte("no config"))).WithOptions(WithStrictParsing())
+				Expect(s.Init(nil)).To(MatchError(ErrFileFormatNotSupported))
+			})
 		})
 		Describe("Read", func() {
 			var field *Field
@@ -171,6 +290,82 @@ test:
 				Expect(err).ToNot(HaveOccurred())
 				Expect(val).To(Equal("1235"))
 			})
+			Context("strict mode", func() {
+				BeforeEach(func() {
+					s = NewReadersSource(
+						newNamedReader(bytes.NewReader([]byte("base:\n  test: abc\n  other: {}\n")), "config.yml"),
+					).WithOptions(WithStrictParsing())
+					Expect(s.Init(nil)).To(Succeed())
+
+					field = &Field{base: []Field{{name: "base"}}, name: "test", value: reflect.ValueOf(0)}
+				})
+				It("returns the key, position and expected type for type mismatches", func() {
+					_, err := s.Read(field)
+					Expect(err).To(MatchError(ErrTypeMismatch))
+					Expect(err).To(MatchError("config.yml:2:9: base.test: type mismatch when trying to assign: expected int"))
+				})
+				It("returns type mismatches for structs", func() {
+					field.name = "other"
+					field.value = reflect.ValueOf(time.Time{})
+
+					_, err := s.Read(field)
+					Expect(err).To(MatchError(ErrTypeMismatch))
+				})
+				It("returns matching values", func() {
+					field.value = reflect.ValueOf("")
+
+					val, err := s.Read(field)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(val).To(Equal("abc"))
+				})
+				It("accepts sections for nested structs", func() {
+					cfg := struct {
+						DB struct {
+							Host string
+							Port int
+						}
+					}{}
+
+					Expect(New(NewReadersSource(
+						newNamedReader(bytes.NewReader([]byte("db: {host: h, port: 5}\n")), "config.yml"),
+					).WithOptions(WithStrictParsing())).Get(&cfg)).To(Succeed())
+					Expect(cfg.DB.Host).To(Equal("h"))
+					Expect(cfg.DB.Port).To(Equal(5))
+				})
+				It("returns type mismatches for nested structs that are not sections", func() {
+					cfg := struct {
+						DB struct {
+							Host string
+						}
+					}{}
+
+					err := New(NewReadersSource(
+						newNamedReader(bytes.NewReader([]byte("db: abc\n")), "config.yml"),
+					).WithOptions(WithStrictParsing())).Get(&cfg)
+					Expect(err).To(MatchError(ErrTypeMismatch))
+				})
+			})
+		})
+		Describe("Locate", func() {
+			var field *Field
+			BeforeEach(func() {
+				s = &ReadersSource{
+					fileMaps: []*ciMap{
+						{m: map[string]interface{}{"base": map[string]interface{}{"test": "1234"}}},
+						{m: map[string]interface{}{"other": "1235"}},
+					},
+					names: []string{"first", "second"},
+				}
+
+				field = &Field{base: []Field{{name: "base"}}, name: "test"}
+			})
+			It("returns the key and the name of the reader containing it", func() {
+				Expect(s.Locate(field)).To(Equal("base.test (first)"))
+			})
+			It("returns only the key if no reader contains it", func() {
+				field.name = "missing"
+				Expect(s.Locate(field)).To(Equal("base.missing"))
+			})
 		})
 	})
 })
@@ -0,0 +1,148 @@
+package alligotor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ProvenanceReport describes where the configuration values that were set by Collector.GetWithReport came from.
+// It contains one FieldReport for every field of the config struct in the order in which the fields are defined.
+type ProvenanceReport struct {
+	Fields []FieldReport
+}
+
+// FieldReport contains the provenance information for a single field of the config struct.
+type FieldReport struct {
+	// Path is the field's path in the config struct, e.g. "DB.Timeout".
+	Path string
+	// Origin describes the source that set the effective value.
+	// It is nil if no source provided a value and the field kept its preset value.
+	Origin *Origin
+	// Shadowed contains the lower-priority sources that also provided a value that was overwritten afterwards.
+	// They are ordered in the order they were applied.
+	Shadowed []Origin
+}
+
+// Origin describes a value that was provided by a ConfigSource.
+type Origin struct {
+	// Source is the config source that returned the value.
+	Source ConfigSource
+	// Key is the key the value was found under, e.g. the name of the environment variable.
+	// It is only set if the source implements the ConfigSourceLocator interface.
+	Key string
+	// Value is the raw value as returned by the source. Byte slices are converted to strings.
+	// For secret fields the value is redacted.
+	Value interface{}
+}
+
+func newReport(fields []Field) *ProvenanceReport {
+	report := &ProvenanceReport{Fields: make([]FieldReport, 0, len(fields))}
+
+	for i := range fields {
+		report.Fields = append(report.Fields, FieldReport{Path: fieldPath(&fields[i])})
+	}
+
+	return report
+}
+
+// Field returns the FieldReport for a certain field path like "DB.Timeout".
+// The path is matched case-insensitively.
+func (r *ProvenanceReport) Field(path string) (FieldReport, bool) {
+	for _, fieldReport := range r.Fields {
+		if strings.EqualFold(fieldReport.Path, path) {
+			return fieldReport, true
+		}
+	}
+
+	return FieldReport{}, false
+}
+
+// String returns a human-readable description of the report with one line per field that was set by any source.
+func (r *ProvenanceReport) String() string {
+	var builder strings.Builder
+
+	for _, fieldReport := range r.Fields {
+		if fieldReport.Origin == nil {
+			continue
+		}
+
+		builder.WriteString(fieldReport.String())
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// String returns a human-readable description of where the field's value came from.
+func (f FieldReport) String() string {
+	if f.Origin == nil {
+		return fmt.Sprintf("%s: not set", f.Path)
+	}
+
+	line := fmt.Sprintf("%s = %v from %s", f.Path, f.Origin.Value, f.Origin)
+
+	shadowed := make([]string, 0, len(f.Shadowed))
+	for _, origin := range f.Shadowed {
+		shadowed = append(shadowed, fmt.Sprintf("%v from %s", origin.Value, origin))
+	}
+
+	if len(shadowed) > 0 {
+		line += ", shadows " + strings.Join(shadowed, ", ")
+	}
+
+	return line
+}
+
+// String returns the source's type name and the key the value was found under.
+func (o Origin) String() string {
+	name := reflect.Indirect(reflect.ValueOf(o.Source)).Type().Name()
+	if o.Key == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s %s", name, o.Key)
+}
+
+// record saves the value a source returned for the field with the given index.
+// If a value was already recorded before it is moved to the shadowed values.
+func (r *ProvenanceReport) record(index int, source ConfigSource, field *Field, value interface{}) {
+	if value == nil {
+		return
+	}
+
+	if bytes, ok := value.([]byte); ok {
+		if bytes == nil {
+			return
+		}
+
+		value = string(bytes)
+	}
+
+	if isSecret(field) {
+		value = redacted
+	}
+
+	origin := &Origin{Source: source, Value: value}
+	if locator, ok := source.(ConfigSourceLocator); ok {
+		origin.Key = locator.Locate(field)
+	}
+
+	fieldReport := &r.Fields[index]
+	if fieldReport.Origin != nil {
+		fieldReport.Shadowed = append(fieldReport.Shadowed, *fieldReport.Origin)
+	}
+
+	fieldReport.Origin = origin
+}
+
+// fieldPath returns the path of the field in the config struct joined by dots, e.g. "DB.Timeout".
+func fieldPath(f *Field) string {
+	names := make([]string, 0, len(f.Base())+1)
+
+	for _, base := range f.Base() {
+		names = append(names, base.Name())
+	}
+
+	return strings.Join(append(names, f.Name()), ".")
+}
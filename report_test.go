@@ -0,0 +1,76 @@
+package alligotor
+
+import (
+	"os"
+	"path"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("report", func() {
+	Describe("GetWithReport", func() {
+		var (
+			tempDir  string
+			filePath string
+			env      *EnvSource
+			files    *FilesSource
+			c        *Collector
+			cfg      struct {
+				Name string
+				DB   struct {
+					Timeout time.Duration
+				}
+			}
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = os.MkdirTemp("", "tests*")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			filePath = path.Join(tempDir, "config.yml")
+			Expect(os.WriteFile(filePath, []byte("db:\n  timeout: 2m\n"), 0600)).To(Succeed())
+			Expect(os.Setenv("REPORT_DB_TIMEOUT", "1m")).To(Succeed())
+
+			files = NewFilesSource(path.Join(tempDir, "config.*"))
+			env = NewEnvSource("REPORT")
+			c = New(files, env)
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+			Expect(os.Unsetenv("REPORT_DB_TIMEOUT")).To(Succeed())
+		})
+
+		It("contains the winning source and the shadowed ones", func() {
+			report, err := c.GetWithReport(&cfg)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cfg.DB.Timeout).To(Equal(time.Minute))
+
+			fieldReport, ok := report.Field("db.timeout")
+			Expect(ok).To(BeTrue())
+			Expect(fieldReport.Path).To(Equal("DB.Timeout"))
+			Expect(fieldReport.Origin).To(Equal(&Origin{Source: env, Key: "REPORT_DB_TIMEOUT", Value: "1m"}))
+			Expect(fieldReport.Shadowed).To(Equal([]Origin{
+				{Source: files, Key: "DB.Timeout (" + filePath + ")", Value: "2m"},
+			}))
+		})
+		It("has no origin for fields that were not set", func() {
+			report, err := c.GetWithReport(&cfg)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fieldReport, ok := report.Field("Name")
+			Expect(ok).To(BeTrue())
+			Expect(fieldReport.Origin).To(BeNil())
+			Expect(fieldReport.Shadowed).To(BeEmpty())
+		})
+		It("can be printed", func() {
+			report, err := c.GetWithReport(&cfg)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(report.String()).To(Equal(
+				"DB.Timeout = 1m from EnvSource REPORT_DB_TIMEOUT, shadows 2m from FilesSource DB.Timeout (" + filePath + ")\n",
+			))
+		})
+	})
+})
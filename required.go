@@ -0,0 +1,80 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const requiredKey = "required"
+
+var ErrRequiredNotSet = errors.New("required config values not set")
+
+// RequiredError is returned by Collector.Get if fields marked with `config:"required=true"` were not set
+// by any of the sources.
+type RequiredError struct {
+	Fields []MissingField
+}
+
+// MissingField describes a required field that was not set by any source.
+type MissingField struct {
+	// Path is the field's path in the config struct, e.g. "DB.Password".
+	Path string
+	// Keys contains the keys that could have been used to set the field, e.g. the environment variable's name.
+	// They are collected from all sources that implement the ConfigSourceLocator interface.
+	Keys []string
+}
+
+func (e *RequiredError) Error() string {
+	missing := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		missing = append(missing, field.String())
+	}
+
+	return fmt.Sprintf("%s: %s", ErrRequiredNotSet, strings.Join(missing, "; "))
+}
+
+// Unwrap makes it possible to check for ErrRequiredNotSet using errors.Is.
+func (e *RequiredError) Unwrap() error {
+	return ErrRequiredNotSet
+}
+
+func (m MissingField) String() string {
+	if len(m.Keys) == 0 {
+		return m.Path
+	}
+
+	return fmt.Sprintf("%s (set via %s)", m.Path, strings.Join(m.Keys, ", "))
+}
+
+// checkRequired returns a RequiredError if any of the required fields has no origin in the report.
+func checkRequired(fields []Field, report *ProvenanceReport, sources []ConfigSource) error {
+	var missing []MissingField
+
+	for i := range fields {
+		if fields[i].Configs()[requiredKey] != "true" || report.Fields[i].Origin != nil {
+			continue
+		}
+
+		missingField := MissingField{Path: fieldPath(&fields[i])}
+
+		for _, source := range sources {
+			locator, ok := source.(ConfigSourceLocator)
+			if !ok {
+				continue
+			}
+
+			if key := locator.Locate(&fields[i]); key != "" {
+				missingField.Keys = append(missingField.Keys, key)
+			}
+		}
+
+		missing = append(missing, missingField)
+	}
+
+	if len(missing) > 0 {
+		return &RequiredError{Fields: missing}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package alligotor
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("required", func() {
+	var cfg struct {
+		Name string
+		DB   struct {
+			Host     string `config:"required=true"`
+			Password string `config:"required=true,env=pass"`
+		}
+	}
+
+	BeforeEach(func() {
+		cfg.DB.Host = ""
+		cfg.DB.Password = ""
+	})
+
+	It("returns error listing all missing fields with their keys", func() {
+		err := New(NewEnvSource("APP"), pathSource{}).Get(&cfg)
+		Expect(err).To(MatchError(ErrRequiredNotSet))
+
+		requiredErr, ok := err.(*RequiredError)
+		Expect(ok).To(BeTrue())
+		Expect(requiredErr.Fields).To(Equal([]MissingField{
+			{Path: "DB.Host", Keys: []string{"APP_DB_HOST"}},
+			{Path: "DB.Password", Keys: []string{"APP_DB_PASS"}},
+		}))
+		Expect(err.Error()).To(Equal(
+			"required config values not set: DB.Host (set via APP_DB_HOST); DB.Password (set via APP_DB_PASS)",
+		))
+	})
+	It("succeeds if all required fields are set by any source", func() {
+		Expect(New(pathSource{"DB.Host": "host", "DB.Password": []byte("value")}).Get(&cfg)).To(Succeed())
+		Expect(cfg.DB.Password).To(Equal("value"))
+	})
+	It("does not count preset values", func() {
+		cfg.DB.Host = "preset"
+		cfg.DB.Password = "preset"
+
+		err := New().Get(&cfg)
+		Expect(err).To(MatchError(ErrRequiredNotSet))
+	})
+})
@@ -0,0 +1,145 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const refPrefix = "ref+"
+
+var (
+	ErrUnknownResolver = errors.New("unknown reference scheme")
+	ErrResolveRef      = errors.New("failed to resolve reference")
+
+	errEmptyCommand = errors.New("empty command")
+	errEnvNotSet    = errors.New("environment variable not set")
+)
+
+// Resolver resolves a reference to its actual value. It receives everything after "ref+<scheme>://".
+type Resolver func(ref string) (string, error)
+
+// resolverRegistry holds all known resolvers by their scheme.
+type resolverRegistry struct {
+	mu        sync.RWMutex
+	resolvers map[string]Resolver
+}
+
+//nolint:gochecknoglobals // registry just like in image package
+var resolvers = &resolverRegistry{
+	resolvers: map[string]Resolver{
+		"file": resolveFile,
+		"env":  resolveEnv,
+	},
+}
+
+// RegisterResolver registers a Resolver for values in the format "ref+<scheme>://<ref>".
+// Registering a resolver for an existing scheme replaces it.
+//
+// If the Collector is created with WithReferences, references are resolved in Collector.Get for the values of all
+// sources before they are parsed into the field's type.
+// The following schemes are registered by default:
+//   - file: reads the file at the given path, e.g. ref+file:///etc/tls/ca.pem
+//   - env: reads the given environment variable, e.g. ref+env://DB_PASSWORD
+//
+// Running commands is supported by ExecResolver, which needs to be registered explicitly.
+// Trailing newlines are removed from the values of all predefined resolvers.
+func RegisterResolver(scheme string, resolver Resolver) {
+	resolvers.mu.Lock()
+	defer resolvers.mu.Unlock()
+
+	resolvers.resolvers[scheme] = resolver
+}
+
+func (r *resolverRegistry) get(scheme string) (Resolver, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resolver, ok := r.resolvers[scheme]
+
+	return resolver, ok
+}
+
+// resolveRef replaces string values in the format "ref+<scheme>://<ref>" by the value returned from the scheme's
+// Resolver. The type of the value ([]byte or string) is kept. All other values are returned unchanged.
+func resolveRef(value interface{}) (interface{}, error) {
+	var ref string
+
+	switch typedVal := value.(type) {
+	case []byte:
+		ref = string(typedVal)
+	case string:
+		ref = typedVal
+	default:
+		return value, nil
+	}
+
+	if !strings.HasPrefix(ref, refPrefix) {
+		return value, nil
+	}
+
+	scheme, rest, ok := strings.Cut(strings.TrimPrefix(ref, refPrefix), "://")
+	if !ok {
+		return value, nil
+	}
+
+	resolver, ok := resolvers.get(scheme)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", scheme, ErrUnknownResolver)
+	}
+
+	resolved, err := resolver(rest)
+	if err != nil {
+		// the reference itself is not added to the error since it could contain a secret
+		return nil, fmt.Errorf("%w with scheme %s: %w", ErrResolveRef, scheme, err)
+	}
+
+	if _, ok := value.([]byte); ok {
+		return []byte(resolved), nil
+	}
+
+	return resolved, nil
+}
+
+func resolveFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// ExecResolver runs the given command without a shell and uses its output, e.g. ref+exec://pass show db.
+// It is not registered by default since it allows everyone that can set a config value to run commands.
+// To use it, it needs to be registered with RegisterResolver("exec", ExecResolver).
+func ExecResolver(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errEmptyCommand
+	}
+
+	var stderr strings.Builder
+
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // running the configured command is the purpose
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func resolveEnv(name string) (string, error) {
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", errEnvNotSet
+	}
+
+	return strings.TrimRight(val, "\r\n"), nil
+}
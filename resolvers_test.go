@@ -0,0 +1,106 @@
+package alligotor
+
+import (
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("resolvers", func() {
+	Describe("resolveRef", func() {
+		It("reads files", func() {
+			file := path.Join(GinkgoT().TempDir(), "ca.pem")
+			Expect(os.WriteFile(file, []byte("content\n"), os.ModePerm)).To(Succeed())
+
+			val, err := resolveRef([]byte("ref+file://" + file))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(Equal([]byte("content")))
+		})
+		It("does not run commands by default", func() {
+			_, err := resolveRef("ref+exec://echo hello world")
+			Expect(err).To(MatchError(ErrUnknownResolver))
+		})
+		It("reads environment variables", func() {
+			Expect(os.Setenv("RESOLVER_TEST", "value")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "RESOLVER_TEST")
+
+			val, err := resolveRef("ref+env://RESOLVER_TEST")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(Equal("value"))
+
+			_, err = resolveRef("ref+env://RESOLVER_TEST_UNSET")
+			Expect(err).To(MatchError(ErrResolveRef))
+			Expect(err.Error()).ToNot(ContainSubstring("RESOLVER_TEST_UNSET"))
+		})
+		It("returns other values unchanged", func() {
+			for _, val := range []interface{}{"plain", []byte("ref+file"), 1} {
+				resolved, err := resolveRef(val)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resolved).To(Equal(val))
+			}
+		})
+		It("returns an error for unknown schemes", func() {
+			_, err := resolveRef("ref+vault://secret/db")
+			Expect(err).To(MatchError(ErrUnknownResolver))
+		})
+	})
+	Describe("ExecResolver", func() {
+		It("runs commands", func() {
+			val, err := ExecResolver("echo hello world")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(Equal("hello world"))
+		})
+		It("can be registered explicitly", func() {
+			RegisterResolver("exec", ExecResolver)
+			DeferCleanup(func() { delete(resolvers.resolvers, "exec") })
+
+			val, err := resolveRef("ref+exec://echo hello world")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(val).To(Equal("hello world"))
+		})
+	})
+	Describe("RegisterResolver", func() {
+		AfterEach(func() {
+			delete(resolvers.resolvers, "upper")
+		})
+		It("uses custom resolvers in Get", func() {
+			RegisterResolver("upper", func(ref string) (string, error) {
+				return ref + "-resolved", nil
+			})
+
+			cfg := struct {
+				Password Secret[string]
+				Name     string
+			}{}
+
+			Expect(New(pathSource{"Password": []byte("ref+upper://pw"), "Name": "ref+upper://name"}).
+				WithOptions(WithReferences()).Get(&cfg)).To(Succeed())
+			Expect(cfg.Password.Value()).To(Equal("pw-resolved"))
+			Expect(cfg.Name).To(Equal("name-resolved"))
+		})
+		It("only resolves references if they are enabled", func() {
+			RegisterResolver("upper", func(ref string) (string, error) {
+				return ref + "-resolved", nil
+			})
+
+			cfg := struct {
+				Name string
+			}{}
+
+			Expect(New(pathSource{"Name": "ref+upper://name"}).Get(&cfg)).To(Succeed())
+			Expect(cfg.Name).To(Equal("ref+upper://name"))
+		})
+		It("returns redacted errors for secrets", func() {
+			cfg := struct {
+				Password Secret[string]
+			}{}
+
+			err := New(pathSource{"Password": []byte("ref+file:///not/existing/secret")}).
+				WithOptions(WithReferences()).Get(&cfg)
+			Expect(err).To(MatchError(ErrResolveRef))
+			Expect(err.Error()).ToNot(ContainSubstring("/not/existing/secret"))
+		})
+	})
+})
@@ -0,0 +1,149 @@
+package alligotor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+const (
+	secretKey = "secret"
+	redacted  = "******"
+)
+
+var ErrInvalidSecret = errors.New("invalid secret value")
+
+// Secret wraps a config value that should never be printed.
+// It is redacted in fmt output, when marshaling to JSON, YAML or text as well as in flag usage, reports
+// and errors returned by Collector.Get. The actual value can only be retrieved with the Value method.
+//
+// Since it implements encoding.TextUnmarshaler it can be read from all sources.
+// The underlying type T is parsed the same way as any other field.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret returns a new Secret containing the given value. It can be used to preset a default.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Value returns the secret's actual value.
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+// String returns a redacted string.
+func (s Secret[T]) String() string {
+	return redacted
+}
+
+// GoString returns a redacted string, so the value is also hidden in "%#v" formatting.
+func (s Secret[T]) GoString() string {
+	return redacted
+}
+
+// Format implements fmt.Formatter to redact the value for every formatting verb.
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, redacted)
+}
+
+// MarshalText returns a redacted text.
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// MarshalJSON returns a redacted JSON string.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
+
+// MarshalYAML returns a redacted YAML string.
+func (s Secret[T]) MarshalYAML() (interface{}, error) {
+	return redacted, nil
+}
+
+// UnmarshalText parses the text into the underlying type.
+// In case of an error, the returned error does not contain the text.
+func (s *Secret[T]) UnmarshalText(text []byte) error {
+	val, err := fromString(reflect.ValueOf(&s.value).Elem(), string(text))
+	if err != nil {
+		return ErrInvalidSecret
+	}
+
+	typedVal, ok := val.(T)
+	if !ok {
+		return ErrInvalidSecret
+	}
+
+	s.value = typedVal
+
+	return nil
+}
+
+func (s Secret[T]) isSecret() {}
+
+// secretMarker is implemented by all Secret types independent of the type parameter.
+type secretMarker interface {
+	isSecret()
+}
+
+// isSecret checks if the field's value should be redacted, either because it's a Secret or because it's marked
+// with `config:"secret=true"`.
+func isSecret(f *Field) bool {
+	if f.Configs()[secretKey] == "true" {
+		return true
+	}
+
+	return f.value.IsValid() && f.Type().Implements(secretMarkerType)
+}
+
+// displayType returns the type that is shown for a field in the usage and docs.
+// For Secrets it's the wrapped type, since that's the format the value needs to be given in.
+func displayType(t reflect.Type) reflect.Type {
+	if !t.Implements(secretMarkerType) {
+		return t
+	}
+
+	method, ok := t.MethodByName("Value")
+	if !ok {
+		return t
+	}
+
+	return method.Type.Out(0)
+}
+
+// unwrapSecret returns the value wrapped by a Secret or the value itself if it's not a Secret.
+func unwrapSecret(value reflect.Value) reflect.Value {
+	if !value.IsValid() || !value.Type().Implements(secretMarkerType) {
+		return value
+	}
+
+	return value.MethodByName("Value").Call(nil)[0]
+}
+
+// redactedError hides the message of an error that could contain a secret value.
+// It can still be checked using errors.Is but the wrapped error can't be retrieved with errors.Unwrap.
+type redactedError struct {
+	path string
+	err  error
+}
+
+// redactError hides the given error's message if the field is a secret.
+func redactError(f *Field, err error) error {
+	if err == nil || !isSecret(f) {
+		return err
+	}
+
+	return &redactedError{path: fieldPath(f), err: err}
+}
+
+func (e *redactedError) Error() string {
+	return fmt.Sprintf("%s: failed to set secret value: %s", e.path, redacted)
+}
+
+func (e *redactedError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
@@ -0,0 +1,137 @@
+package alligotor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("secret", func() {
+	type config struct {
+		Password Secret[string]
+		Port     Secret[int]
+		Token    string `config:"secret=true"`
+	}
+
+	Describe("Secret", func() {
+		var cfg config
+		BeforeEach(func() {
+			cfg = config{Password: NewSecret("hunter2"), Port: NewSecret(5432)}
+		})
+
+		It("returns the actual value", func() {
+			Expect(cfg.Password.Value()).To(Equal("hunter2"))
+			Expect(cfg.Port.Value()).To(Equal(5432))
+		})
+		It("is redacted in fmt output", func() {
+			for _, format := range []string{"%v", "%+v", "%#v", "%s", "%d", "%q"} {
+				Expect(fmt.Sprintf(format, cfg)).ToNot(ContainSubstring("hunter2"))
+				Expect(fmt.Sprintf(format, cfg.Password)).To(Equal(redacted))
+			}
+		})
+		It("is redacted in JSON and YAML", func() {
+			jsonBytes, err := json.Marshal(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(jsonBytes)).To(Equal(`{"Password":"******","Port":"******","Token":""}`))
+
+			yamlBytes, err := yaml.Marshal(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(yamlBytes)).To(Equal("password: '******'\nport: '******'\ntoken: \"\"\n"))
+		})
+		It("parses the underlying type", func() {
+			s := Secret[int]{}
+			Expect(s.UnmarshalText([]byte("42"))).To(Succeed())
+			Expect(s.Value()).To(Equal(42))
+		})
+		It("does not contain the value in parse errors", func() {
+			s := Secret[int]{}
+			err := s.UnmarshalText([]byte("hunter2"))
+			Expect(err).To(MatchError(ErrInvalidSecret))
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+	})
+	Describe("Collector", func() {
+		var cfg config
+		BeforeEach(func() {
+			cfg = config{}
+		})
+
+		It("reads secrets from all kinds of sources", func() {
+			jsonBytes := []byte(`{"port": 5432}`)
+			Expect(New(
+				NewReadersSource(bytes.NewReader(jsonBytes)),
+				pathSource{"Password": []byte("hunter2")},
+			).Get(&cfg)).To(Succeed())
+			Expect(cfg.Password.Value()).To(Equal("hunter2"))
+			Expect(cfg.Port.Value()).To(Equal(5432))
+		})
+		It("redacts secrets in the report", func() {
+			report, err := New(pathSource{"Password": []byte("hunter2"), "Token": []byte("token")}).GetWithReport(&cfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.String()).ToNot(ContainSubstring("hunter2"))
+			Expect(report.String()).ToNot(ContainSubstring("token"))
+
+			fieldReport, _ := report.Field("Token")
+			Expect(fieldReport.Origin.Value).To(Equal(redacted))
+		})
+		It("redacts errors of secret fields", func() {
+			err := New(pathSource{"Token": 1234}).Get(&cfg)
+			Expect(err).To(MatchError(ErrTypeMismatch))
+			Expect(err.Error()).To(Equal("Token: failed to set secret value: ******"))
+
+			err = New(pathSource{"Port": []byte("hunter2")}).Get(&cfg)
+			Expect(err).To(MatchError(ErrInvalidSecret))
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+	})
+	Describe("fields", func() {
+		It("handles secrets as single values", func() {
+			Expect(fieldPaths(&config{})).To(Equal([]string{"Password", "Port", "Token"}))
+		})
+		It("shows the wrapped type", func() {
+			Expect(displayType(reflect.TypeOf(Secret[int]{}))).To(Equal(reflect.TypeOf(0)))
+			Expect(displayType(reflect.TypeOf(""))).To(Equal(reflect.TypeOf("")))
+
+			docs, err := New(NewFlagsSource()).Docs(&config{}, DocsMarkdown)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(docs)).ToNot(ContainSubstring("Secret["))
+		})
+	})
+	Describe("validation", func() {
+		It("validates the actual value", func() {
+			cfg := struct {
+				Password Secret[string] `validate:"oneof=a b"`
+				Port     Secret[int]    `validate:"required,min=1,max=10"`
+			}{Password: NewSecret("a"), Port: NewSecret(5)}
+
+			Expect(validate(structFields(&cfg))).To(Succeed())
+
+			cfg.Password, cfg.Port = NewSecret("hunter2"), NewSecret(0)
+			err := validate(structFields(&cfg))
+			Expect(err).To(MatchError(ErrValidation))
+			Expect(err.(*ValidationError).Violations).To(HaveLen(3))
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+	})
+	Describe("flag usage defaults", func() {
+		It("redacts secrets", func() {
+			cfg := config{Password: NewSecret("hunter2"), Token: "token"}
+			fields, err := getFieldsConfigsFromValue(reflect.ValueOf(&cfg).Elem(), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			defaults := map[string]string{}
+			for i := range fields {
+				defaults[fieldPath(&fields[i])] = defaultValue(&fields[i])
+			}
+
+			Expect(defaults).To(HaveKeyWithValue("Password", redacted))
+			Expect(defaults).To(HaveKeyWithValue("Port", ""))
+			Expect(defaults).To(HaveKeyWithValue("Token", redacted))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const squashKey = "squash"
+
+var (
+	ErrInvalidSquash  = errors.New("only structs can be squashed")
+	ErrFieldCollision = errors.New("field name is used by multiple embedded structs")
+)
+
+// structField is a field of a struct together with the number of squashed structs it's nested in.
+type structField struct {
+	field Field
+	depth int
+}
+
+// shouldSquash checks if the fields of a struct field should be addressed at the level of the parent struct.
+// Embedded structs are squashed by default, which can be disabled with `config:"squash=false"`.
+// Other struct fields can be squashed with `config:"squash=true"`.
+func shouldSquash(fieldType reflect.StructField, fieldValue reflect.Value, configs map[string]string) (bool, error) {
+	isStruct := fieldValue.Kind() == reflect.Struct && !isValueStruct(fieldValue)
+
+	squashStr, ok := configs[squashKey]
+	if !ok {
+		return fieldType.Anonymous && isStruct, nil
+	}
+
+	squash, err := strconv.ParseBool(squashStr)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", fieldType.Name, err)
+	}
+
+	if squash && !isStruct {
+		return false, fmt.Errorf("%s: %w", fieldType.Name, ErrInvalidSquash)
+	}
+
+	return squash, nil
+}
+
+// resolveCollisions removes fields that are shadowed by a field with the same name in a shallower struct,
+// just like Go's rules for promoted fields. If fields with the same name are at the same depth, an error is returned.
+func resolveCollisions(fields []structField) ([]structField, error) {
+	shallowest := map[string]int{}
+	count := map[string]int{}
+
+	for _, f := range fields {
+		name := strings.ToLower(f.field.Name())
+
+		depth, ok := shallowest[name]
+		if ok && depth < f.depth {
+			continue
+		}
+
+		if !ok || f.depth < depth {
+			shallowest[name] = f.depth
+			count[name] = 0
+		}
+
+		count[name]++
+	}
+
+	resolved := make([]structField, 0, len(fields))
+
+	for _, f := range fields {
+		name := strings.ToLower(f.field.Name())
+		if f.depth != shallowest[name] {
+			continue
+		}
+
+		if count[name] > 1 {
+			return nil, fmt.Errorf("%s: %w", fieldPath(&f.field), ErrFieldCollision)
+		}
+
+		resolved = append(resolved, f)
+	}
+
+	if err := checkSourceCollisions(resolved); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// checkSourceCollisions returns an error if a squashed field has the same name as another field for any of the
+// sources, e.g. because of overrides like `config:"env=port"`. Fields that are ignored by a source are not checked.
+func checkSourceCollisions(fields []structField) error {
+	extractors := []struct {
+		source  string
+		extract func(*Field) string
+	}{
+		{source: envKey, extract: extractEnvName},
+		{source: flagKey, extract: extractFlagName},
+		{source: fileKey, extract: extractFileName},
+	}
+
+	for _, extractor := range extractors {
+		seen := map[string]structField{}
+
+		for _, f := range fields {
+			if f.field.IgnoredBy(extractor.source) {
+				continue
+			}
+
+			name := strings.ToLower(extractor.extract(&f.field))
+
+			other, ok := seen[name]
+			if !ok {
+				seen[name] = f
+				continue
+			}
+
+			if f.depth > 0 || other.depth > 0 {
+				return fmt.Errorf("%s: %s name %s: %w", fieldPath(&f.field), extractor.source, name, ErrFieldCollision)
+			}
+		}
+	}
+
+	return nil
+}
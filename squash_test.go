@@ -0,0 +1,115 @@
+package alligotor
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+
+	"github.com/brumhard/alligotor/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("squash", func() {
+	type Common struct {
+		Host string
+		Port int
+	}
+
+	type Other struct {
+		Host string
+	}
+
+	It("squashes embedded structs by default", func() {
+		cfg := struct {
+			Common
+			Name string
+		}{}
+
+		Expect(fieldPaths(&cfg)).To(Equal([]string{"Host", "Port", "Name"}))
+	})
+	It("keeps embedded structs nested with squash=false", func() {
+		cfg := struct {
+			Common `config:"squash=false"`
+		}{}
+
+		Expect(fieldPaths(&cfg)).To(Equal([]string{"Common", "Common.Host", "Common.Port"}))
+	})
+	It("squashes named struct fields with squash=true", func() {
+		cfg := struct {
+			DB Common `config:"squash=true"`
+		}{}
+
+		Expect(fieldPaths(&cfg)).To(Equal([]string{"Host", "Port"}))
+	})
+	It("lets shallower fields win", func() {
+		cfg := struct {
+			Common
+			Port string
+		}{}
+
+		Expect(fieldPaths(&cfg)).To(Equal([]string{"Host", "Port"}))
+		Expect(structFields(&cfg)[1].Type()).To(Equal(reflect.TypeOf("")))
+	})
+	It("returns an error for collisions at the same depth", func() {
+		cfg := struct {
+			Common
+			Other
+		}{}
+
+		_, err := getFieldsConfigsFromValue(reflect.ValueOf(&cfg).Elem(), nil)
+		Expect(err).To(MatchError(ErrFieldCollision))
+	})
+	It("returns an error for collisions of the names used by the sources", func() {
+		fileCollision := struct {
+			Common
+			Addr string `config:"file=host"`
+		}{}
+
+		_, err := getFieldsConfigsFromValue(reflect.ValueOf(&fileCollision).Elem(), nil)
+		Expect(err).To(MatchError(ErrFieldCollision))
+		Expect(err).To(MatchError(ContainSubstring("file name host")))
+
+		envCollision := struct {
+			Common
+			Addr string `config:"env=port"`
+		}{}
+
+		_, err = getFieldsConfigsFromValue(reflect.ValueOf(&envCollision).Elem(), nil)
+		Expect(err).To(MatchError(ErrFieldCollision))
+	})
+	It("ignores collisions for sources that ignore the field", func() {
+		cfg := struct {
+			Common
+			Addr string `config:"env=port,sources=file"`
+		}{}
+
+		Expect(fieldPaths(&cfg)).To(Equal([]string{"Host", "Port", "Addr"}))
+	})
+	It("returns an error for squashing non-structs", func() {
+		cfg := struct {
+			Port int `config:"squash=true"`
+		}{}
+
+		_, err := getFieldsConfigsFromValue(reflect.ValueOf(&cfg).Elem(), nil)
+		Expect(err).To(MatchError(ErrInvalidSquash))
+	})
+	It("reads squashed fields at the parent level from all sources", func() {
+		cfg := struct {
+			test.DBConfig
+			Server Common `config:"squash=true"`
+		}{}
+
+		Expect(os.Setenv("APP_PORT", "2")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "APP_PORT")
+
+		c := New(
+			NewReadersSource(bytes.NewReader([]byte(`{"logLevel": "debug", "host": "db"}`))),
+			NewEnvSource("app"),
+		)
+		Expect(c.Get(&cfg)).To(Succeed())
+		Expect(cfg.LogLevel).To(Equal("debug"))
+		Expect(cfg.Server.Port).To(Equal(2))
+		Expect(cfg.Server.Host).To(Equal("db"))
+	})
+})
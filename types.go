@@ -8,15 +8,17 @@ import (
 
 //nolint:gochecknoglobals // package lvl type definitions
 var (
-	zeroString      = ""
-	zeroDuration    = time.Duration(0)
-	stringType      = reflect.TypeOf(zeroString)
-	stringPtrType   = reflect.TypeOf(&zeroString)
-	durationType    = reflect.TypeOf(zeroDuration)
-	durationPtrType = reflect.TypeOf(&zeroDuration)
-	timeType        = reflect.TypeOf(time.Time{})
-	timePtrType     = reflect.TypeOf(&time.Time{})
-	stringSliceType = reflect.TypeOf([]string{})
-	stringMapType   = reflect.TypeOf(map[string]string{})
-	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	zeroString       = ""
+	zeroDuration     = time.Duration(0)
+	stringType       = reflect.TypeOf(zeroString)
+	stringPtrType    = reflect.TypeOf(&zeroString)
+	durationType     = reflect.TypeOf(zeroDuration)
+	durationPtrType  = reflect.TypeOf(&zeroDuration)
+	timeType         = reflect.TypeOf(time.Time{})
+	timePtrType      = reflect.TypeOf(&time.Time{})
+	stringSliceType  = reflect.TypeOf([]string{})
+	stringMapType    = reflect.TypeOf(map[string]string{})
+	textUnmarshaler  = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshaler    = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	secretMarkerType = reflect.TypeOf((*secretMarker)(nil)).Elem()
 )
@@ -0,0 +1,185 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownKeys = errors.New("unknown config keys")
+
+// UnknownKeysError is returned by the ReadersSource's Init if WithDisallowUnknownKeys is used and the config files
+// contain keys that don't map onto any field of the config struct.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+// UnknownKey describes a key in a config file that doesn't map onto any field.
+type UnknownKey struct {
+	// File is the name of the file or reader containing the key.
+	File string
+	// Line and Column start at 1, 0 means unknown.
+	Line   int
+	Column int
+	// Key is the key's path in the file joined by ".", e.g. "db.hostnmae".
+	Key string
+	// Suggestion contains the most similar known key or is empty if there is none.
+	Suggestion string
+}
+
+func (e *UnknownKeysError) Error() string {
+	keys := make([]string, 0, len(e.Keys))
+	for _, key := range e.Keys {
+		keys = append(keys, key.String())
+	}
+
+	return fmt.Sprintf("%s: %s", ErrUnknownKeys, strings.Join(keys, "; "))
+}
+
+// Unwrap makes it possible to check for ErrUnknownKeys using errors.Is.
+func (e *UnknownKeysError) Unwrap() error {
+	return ErrUnknownKeys
+}
+
+func (k UnknownKey) String() string {
+	location := (&ParseError{File: k.File, Line: k.Line, Column: k.Column, Key: k.Key}).location()
+	if k.Suggestion == "" {
+		return location
+	}
+
+	return fmt.Sprintf("%s (did you mean %s?)", location, k.Suggestion)
+}
+
+// WithDisallowUnknownKeys makes the ReadersSource's Init return an *UnknownKeysError if the config files contain keys
+// that don't map onto any field of the config struct, including suggestions for misspelled keys.
+func WithDisallowUnknownKeys() ReadersOption {
+	return func(o *readersOptions) {
+		o.disallowUnknownKeys = true
+	}
+}
+
+// checkUnknownKeys returns an *UnknownKeysError if any of the fileMaps contains keys that are not used by the fields.
+func (s *ReadersSource) checkUnknownKeys(fields []Field) error {
+	// known contains all keys that are read by the fields in lowercase, and whether the field contains subfields
+	known := map[string]bool{}
+	knownKeys := make([]string, 0, len(fields))
+
+	for i := range fields {
+		f := &fields[i]
+		key := strings.Join(append(f.BaseNames(extractFileName), extractFileName(f)), ".")
+		known[strings.ToLower(key)] = f.value.Kind() == reflect.Struct && !isValueStruct(f.value)
+		knownKeys = append(knownKeys, key)
+	}
+
+	var unknown []UnknownKey
+
+	for i, m := range s.fileMaps {
+		var fileUnknown []UnknownKey
+
+		walkKeys(m.m, "", func(key string) bool {
+			container, ok := known[strings.ToLower(key)]
+			if ok {
+				return container
+			}
+
+			unknownKey := UnknownKey{File: s.names[i], Key: key, Suggestion: suggest(key, knownKeys)}
+			if i < len(s.positions) {
+				pos := s.positions[i][strings.ToLower(key)]
+				unknownKey.Line, unknownKey.Column = pos.Line, pos.Column
+			}
+
+			fileUnknown = append(fileUnknown, unknownKey)
+
+			return false
+		})
+
+		sort.Slice(fileUnknown, func(a, b int) bool {
+			return fileUnknown[a].Key < fileUnknown[b].Key
+		})
+
+		unknown = append(unknown, fileUnknown...)
+	}
+
+	if len(unknown) > 0 {
+		return &UnknownKeysError{Keys: unknown}
+	}
+
+	return nil
+}
+
+// walkKeys calls visit for every key in the map with its path joined by ".".
+// It only descends into nested maps if visit returns true.
+func walkKeys(m map[string]interface{}, path string, visit func(key string) bool) {
+	for key, val := range m {
+		if path != "" {
+			key = path + "." + key
+		}
+
+		if !visit(key) {
+			continue
+		}
+
+		if nested, ok := val.(map[string]interface{}); ok {
+			walkKeys(nested, key, visit)
+		}
+	}
+}
+
+// suggest returns the candidate that is most similar to the given input, compared case-insensitively.
+// If no candidate is similar enough, an empty string is returned.
+func suggest(input string, candidates []string) string {
+	// allow roughly one edit for every three characters
+	maxDistance := len(input)/3 + 1
+	suggestion := ""
+
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(input), strings.ToLower(candidate))
+		if distance <= maxDistance {
+			maxDistance = distance - 1
+			suggestion = candidate
+		}
+	}
+
+	return suggestion
+}
+
+// levenshtein returns the minimum number of single character edits needed to change a into b.
+func levenshtein(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := range runesA {
+		current[0] = i + 1
+
+		for j := range runesB {
+			cost := 1
+			if runesA[i] == runesB[j] {
+				cost = 0
+			}
+
+			current[j+1] = minInt(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(runesB)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,74 @@
+package alligotor
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("unknown keys", func() {
+	type testConfig struct {
+		DB struct {
+			HostName string
+			Port     int `config:"file=dbport"`
+		}
+		Labels  map[string]string
+		Started time.Time
+	}
+
+	var (
+		cfg     testConfig
+		newFile = func(content string) *Collector {
+			return New(NewReadersSource(
+				newNamedReader(bytes.NewReader([]byte(content)), "config.yml"),
+			).WithOptions(WithDisallowUnknownKeys()))
+		}
+	)
+
+	BeforeEach(func() {
+		cfg = testConfig{}
+	})
+	It("accepts files containing only known keys", func() {
+		err := newFile("db:\n  hostname: test\n  DBPORT: 1\nlabels:\n  any: value\nstarted: 2021-01-01T00:00:00Z\n").
+			Get(&cfg)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.DB.Port).To(Equal(1))
+		Expect(cfg.Labels).To(Equal(map[string]string{"any": "value"}))
+	})
+	It("returns all unknown keys with positions and suggestions", func() {
+		err := newFile("db:\n  hostnmae: test\n  port: 1\nother: true\n").Get(&cfg)
+		Expect(err).To(MatchError(ErrUnknownKeys))
+
+		var unknownErr *UnknownKeysError
+		Expect(errors.As(err, &unknownErr)).To(BeTrue())
+		Expect(unknownErr.Keys).To(Equal([]UnknownKey{
+			{File: "config.yml", Line: 2, Column: 13, Key: "db.hostnmae", Suggestion: "DB.HostName"},
+			{File: "config.yml", Line: 3, Column: 9, Key: "db.port", Suggestion: "DB.dbport"},
+			{File: "config.yml", Line: 4, Column: 8, Key: "other"},
+		}))
+		Expect(err).To(MatchError("unknown config keys: config.yml:2:13: db.hostnmae (did you mean DB.HostName?); " +
+			"config.yml:3:9: db.port (did you mean DB.dbport?); config.yml:4:8: other"))
+	})
+	It("ignores unknown keys by default", func() {
+		c := New(NewReadersSource(bytes.NewReader([]byte("other: true"))))
+		Expect(c.Get(&cfg)).To(Succeed())
+	})
+	Describe("suggest", func() {
+		It("returns the most similar candidate", func() {
+			Expect(suggest("db.hostnmae", []string{"DB.Port", "DB.HostName", "DB.Host"})).To(Equal("DB.HostName"))
+		})
+		It("returns nothing if no candidate is similar enough", func() {
+			Expect(suggest("other", []string{"DB.Port", "DB.HostName"})).To(BeEmpty())
+		})
+	})
+	Describe("levenshtein", func() {
+		It("counts the edits", func() {
+			Expect(levenshtein("kitten", "sitting")).To(Equal(3))
+			Expect(levenshtein("", "abc")).To(Equal(3))
+			Expect(levenshtein("abc", "abc")).To(Equal(0))
+		})
+	})
+})
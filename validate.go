@@ -0,0 +1,312 @@
+package alligotor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const validateTagKey = "validate"
+
+var (
+	ErrValidation            = errors.New("config validation failed")
+	ErrUnknownValidationRule = errors.New("unknown validation rule")
+	ErrInvalidValidationRule = errors.New("invalid validation rule")
+)
+
+// ValidationError is returned by Collector.Get if any of the rules defined in the validate struct tags are violated.
+// It contains all violations and not only the first one.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Violation describes a single validation rule that was violated by a field's value.
+type Violation struct {
+	// Path is the field's path in the config struct, e.g. "DB.Timeout".
+	Path string
+	// Rule is the violated rule as defined in the struct tag, e.g. "min=1".
+	Rule string
+	// Message describes the violation.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(messages, "; "))
+}
+
+// Unwrap makes it possible to check for ErrValidation using errors.Is.
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+// validateFn checks a value against a rule's parameter and returns a message if the value violates the rule.
+// An error is only returned if the rule itself is invalid.
+type validateFn func(value reflect.Value, param string) (string, error)
+
+//nolint:gochecknoglobals // package lvl lookup table
+var validators = map[string]validateFn{
+	"required":    validateRequired,
+	"min":         validateMin,
+	"max":         validateMax,
+	"len":         validateLen,
+	"oneof":       validateOneOf,
+	"regex":       validateRegex,
+	"url":         validateURL,
+	"hostport":    validateHostPort,
+	"file-exists": validateFileExists,
+}
+
+// validate checks all fields against the rules defined in their validate struct tags.
+// All violations are collected and returned in a ValidationError.
+// Rules are separated by "," and parameters are separated from the rule's name with "=", for example
+// `validate:"required,min=1,oneof=a b c"`. Commas in parameters can be escaped with a backslash.
+func validate(fields []Field) error {
+	var violations []Violation
+
+	for i := range fields {
+		if fields[i].validate == "" {
+			continue
+		}
+
+		for _, rule := range splitTag(fields[i].validate) {
+			name, param, _ := strings.Cut(rule, "=")
+
+			validator, ok := validators[name]
+			if !ok {
+				return fmt.Errorf("%s: %s: %w", fieldPath(&fields[i]), name, ErrUnknownValidationRule)
+			}
+
+			value, isNil := indirectValue(fields[i].value)
+			if isNil && name != "required" {
+				// nil pointers are only checked for required, all other rules need a value
+				continue
+			}
+
+			// secrets are validated by their actual value, the messages don't contain it
+			value = unwrapSecret(value)
+
+			message, err := validator(value, param)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", fieldPath(&fields[i]), rule, err)
+			}
+
+			if message != "" {
+				violations = append(violations, Violation{Path: fieldPath(&fields[i]), Rule: rule, Message: message})
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// indirectValue dereferences pointers and reports if a nil pointer was found.
+func indirectValue(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return value, true
+		}
+
+		value = value.Elem()
+	}
+
+	return value, false
+}
+
+func validateRequired(value reflect.Value, _ string) (string, error) {
+	if value.IsZero() {
+		return "is required", nil
+	}
+
+	return "", nil
+}
+
+func validateMin(value reflect.Value, param string) (string, error) {
+	cmp, err := compare(value, param)
+	if err != nil {
+		return "", err
+	}
+
+	if cmp < 0 {
+		return fmt.Sprintf("must be at least %s", param), nil
+	}
+
+	return "", nil
+}
+
+func validateMax(value reflect.Value, param string) (string, error) {
+	cmp, err := compare(value, param)
+	if err != nil {
+		return "", err
+	}
+
+	if cmp > 0 {
+		return fmt.Sprintf("must be at most %s", param), nil
+	}
+
+	return "", nil
+}
+
+func validateLen(value reflect.Value, param string) (string, error) {
+	expected, err := strconv.Atoi(param)
+	if err != nil {
+		return "", ErrInvalidValidationRule
+	}
+
+	length, ok := valueLen(value)
+	if !ok {
+		return "", ErrInvalidValidationRule
+	}
+
+	if length != expected {
+		return fmt.Sprintf("must have length %d", expected), nil
+	}
+
+	return "", nil
+}
+
+func validateOneOf(value reflect.Value, param string) (string, error) {
+	allowed := strings.Fields(param)
+	actual := fmt.Sprint(value.Interface())
+
+	for _, a := range allowed {
+		if actual == a {
+			return "", nil
+		}
+	}
+
+	return fmt.Sprintf("must be one of [%s]", strings.Join(allowed, " ")), nil
+}
+
+func validateRegex(value reflect.Value, param string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", ErrInvalidValidationRule
+	}
+
+	re, err := regexp.Compile(param)
+	if err != nil {
+		return "", err
+	}
+
+	if !re.MatchString(value.String()) {
+		return fmt.Sprintf("must match %s", param), nil
+	}
+
+	return "", nil
+}
+
+func validateURL(value reflect.Value, _ string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", ErrInvalidValidationRule
+	}
+
+	u, err := url.Parse(value.String())
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "must be a valid URL", nil
+	}
+
+	return "", nil
+}
+
+func validateHostPort(value reflect.Value, _ string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", ErrInvalidValidationRule
+	}
+
+	_, port, err := net.SplitHostPort(value.String())
+	if err != nil {
+		return "must be in the format host:port", nil
+	}
+
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "must have a valid port", nil
+	}
+
+	return "", nil
+}
+
+func validateFileExists(value reflect.Value, _ string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", ErrInvalidValidationRule
+	}
+
+	if _, err := os.Stat(value.String()); err != nil {
+		return "must be an existing file", nil
+	}
+
+	return "", nil
+}
+
+// compare compares the value with the given parameter and returns -1, 0 or 1 if the value is
+// less, equal or greater than the parameter.
+// Numbers are compared by their value, all types that have a length like strings, slices and maps are compared by
+// their length.
+// The parameter is parsed into the value's type, so for example for time.Duration "1s" is supported.
+func compare(value reflect.Value, param string) (int, error) {
+	if length, ok := valueLen(value); ok {
+		expected, err := strconv.Atoi(param)
+		if err != nil {
+			return 0, ErrInvalidValidationRule
+		}
+
+		return compareOrdered(length, expected), nil
+	}
+
+	parsed, err := fromString(reflect.New(value.Type()).Elem(), param)
+	if err != nil {
+		return 0, ErrInvalidValidationRule
+	}
+
+	paramValue := reflect.ValueOf(parsed)
+
+	//nolint:exhaustive // only numbers can be compared
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(value.Int(), paramValue.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compareOrdered(value.Uint(), paramValue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(value.Float(), paramValue.Float()), nil
+	}
+
+	return 0, ErrInvalidValidationRule
+}
+
+func compareOrdered[T int | int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func valueLen(value reflect.Value) (int, bool) {
+	//nolint:exhaustive // only types with a length are relevant
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len(), true
+	}
+
+	return 0, false
+}
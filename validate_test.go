@@ -0,0 +1,130 @@
+package alligotor
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validate", func() {
+	fieldsOf := func(v interface{}) []Field {
+		fields, err := getFieldsConfigsFromValue(reflect.ValueOf(v).Elem(), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		return fields
+	}
+
+	It("succeeds if no rule is violated", func() {
+		cfg := struct {
+			Port    int           `validate:"required,min=1,max=65535"`
+			Timeout time.Duration `validate:"min=1s,max=1m"`
+			Level   string        `validate:"oneof=debug info"`
+			Name    string        `validate:"len=3,regex=^[a-z]+$"`
+			URL     string        `validate:"url"`
+			Addr    string        `validate:"hostport"`
+		}{Port: 80, Timeout: time.Second, Level: "info", Name: "abc", URL: "https://example.com", Addr: "localhost:80"}
+
+		Expect(validate(fieldsOf(&cfg))).To(Succeed())
+	})
+	It("collects all violations with their full paths", func() {
+		cfg := struct {
+			DB struct {
+				Host string        `validate:"required"`
+				Port int           `validate:"min=1"`
+				Pool []string      `validate:"max=1"`
+				TTL  time.Duration `validate:"max=1m"`
+			}
+			Level string `validate:"oneof=debug info"`
+			URL   string `validate:"url"`
+			Addr  string `validate:"hostport"`
+		}{Level: "trace", URL: "localhost", Addr: "localhost"}
+		cfg.DB.Pool = []string{"a", "b"}
+		cfg.DB.TTL = time.Hour
+
+		err := validate(fieldsOf(&cfg))
+		Expect(err).To(MatchError(ErrValidation))
+
+		validationErr := &ValidationError{}
+		Expect(err).To(BeAssignableToTypeOf(validationErr))
+		Expect(err.(*ValidationError).Violations).To(Equal([]Violation{
+			{Path: "DB.Host", Rule: "required", Message: "is required"},
+			{Path: "DB.Port", Rule: "min=1", Message: "must be at least 1"},
+			{Path: "DB.Pool", Rule: "max=1", Message: "must be at most 1"},
+			{Path: "DB.TTL", Rule: "max=1m", Message: "must be at most 1m"},
+			{Path: "Level", Rule: "oneof=debug info", Message: "must be one of [debug info]"},
+			{Path: "URL", Rule: "url", Message: "must be a valid URL"},
+			{Path: "Addr", Rule: "hostport", Message: "must be in the format host:port"},
+		}))
+	})
+	It("supports escaped commas in rule parameters", func() {
+		cfg := struct {
+			Code string `validate:"regex=^\\d{1\\,3}$,len=3"`
+		}{Code: "123"}
+
+		Expect(validate(fieldsOf(&cfg))).To(Succeed())
+
+		cfg.Code = "1234"
+		err := validate(fieldsOf(&cfg))
+		Expect(err).To(MatchError(ErrValidation))
+		Expect(err.(*ValidationError).Violations[0].Rule).To(Equal(`regex=^\d{1,3}$`))
+	})
+	It("checks required for nil pointers but skips other rules", func() {
+		cfg := struct {
+			Required *int `validate:"required"`
+			Optional *int `validate:"min=1"`
+		}{}
+
+		err := validate(fieldsOf(&cfg))
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*ValidationError).Violations).To(HaveLen(1))
+		Expect(err.(*ValidationError).Violations[0].Path).To(Equal("Required"))
+	})
+	It("checks if files exist", func() {
+		tempDir, err := os.MkdirTemp("", "tests*")
+		Expect(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(tempDir)
+
+		cfg := struct {
+			File string `validate:"file-exists"`
+		}{File: path.Join(tempDir, "missing")}
+		Expect(validate(fieldsOf(&cfg))).To(MatchError(ErrValidation))
+
+		cfg.File = tempDir
+		Expect(validate(fieldsOf(&cfg))).To(Succeed())
+	})
+	It("returns an error for unknown rules", func() {
+		cfg := struct {
+			Port int `validate:"positive"`
+		}{}
+
+		Expect(validate(fieldsOf(&cfg))).To(MatchError(ErrUnknownValidationRule))
+	})
+	It("returns an error for rules that don't fit the type", func() {
+		cfg := struct {
+			Port int `validate:"url"`
+		}{}
+
+		Expect(validate(fieldsOf(&cfg))).To(MatchError(ErrInvalidValidationRule))
+	})
+	It("is run by the Collector after all sources", func() {
+		cfg := struct {
+			Port int `validate:"min=1"`
+		}{}
+
+		Expect(New().Get(&cfg)).To(MatchError(ErrValidation))
+		Expect(New(&staticSource{value: []byte("1")}).Get(&cfg)).To(Succeed())
+	})
+})
+
+// staticSource returns the same value for every field.
+type staticSource struct {
+	value interface{}
+}
+
+func (s *staticSource) Read(_ *Field) (interface{}, error) {
+	return s.value, nil
+}
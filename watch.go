@@ -0,0 +1,231 @@
+package alligotor
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+const defaultPollInterval = 2 * time.Second
+
+// WatchOption takes a watchConfig as input and modifies it.
+type WatchOption func(*watchConfig)
+
+type watchConfig struct {
+	signals      []os.Signal
+	errorHandler func(error)
+}
+
+// WithWatchSignals overrides the signals that trigger a reload in Collector.Watch. The default is SIGHUP.
+// If no signals are passed, reloading on signals is disabled.
+func WithWatchSignals(signals ...os.Signal) WatchOption {
+	return func(config *watchConfig) {
+		config.signals = signals
+	}
+}
+
+// WithWatchErrorHandler sets a function that is called with errors that occur while reloading the config in
+// Collector.Watch. By default, these errors are ignored and the last valid config is kept.
+func WithWatchErrorHandler(handler func(error)) WatchOption {
+	return func(config *watchConfig) {
+		config.errorHandler = handler
+	}
+}
+
+// Watch initially fills v just like Get and then keeps watching for changes until ctx is done.
+// Whenever a watched input changes all sources are read again into a copy of v's initial state, so values
+// that are preset in the struct keep working as defaults.
+// If the resulting config differs from the last one, callback is invoked with a pointer to the new config
+// which has the same type as v. v itself is not modified after the initial Get.
+//
+// A reload is triggered by a SIGHUP (see WithWatchSignals) or by any source that implements the
+// ConfigSourceWatcher interface, like the FilesSource that polls the files matched by its globs.
+// Errors that occur while reloading don't stop the watch but can be handled with WithWatchErrorHandler.
+//
+// Watch blocks until ctx is done and returns nil in that case.
+func (c *Collector) Watch(ctx context.Context, v interface{}, callback func(v interface{}), opts ...WatchOption) error {
+	config := &watchConfig{
+		signals:      []os.Signal{syscall.SIGHUP},
+		errorHandler: func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if _, err := structValue(v); err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(v)
+	initial := deepCopy(value)
+
+	if err := c.Get(v); err != nil {
+		return err
+	}
+
+	last := deepCopy(value)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	changed := c.startWatchers(ctx, config)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+
+		next := deepCopy(initial)
+		if err := c.Get(next.Interface()); err != nil {
+			config.errorHandler(err)
+			continue
+		}
+
+		if reflect.DeepEqual(next.Interface(), last.Interface()) {
+			continue
+		}
+
+		last = next
+		callback(deepCopy(next).Interface())
+	}
+}
+
+// startWatchers starts watching all sources that implement ConfigSourceWatcher and the configured signals.
+// The returned channel receives a value whenever any of them reports a change.
+func (c *Collector) startWatchers(ctx context.Context, config *watchConfig) <-chan struct{} {
+	changed := make(chan struct{}, 1)
+
+	if len(config.signals) > 0 {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, config.signals...)
+
+		go func() {
+			defer signal.Stop(signals)
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-signals:
+					notify(changed)
+				}
+			}
+		}()
+	}
+
+	for _, source := range c.Sources {
+		watcher, ok := source.(ConfigSourceWatcher)
+		if !ok {
+			continue
+		}
+
+		go func() {
+			if err := watcher.Watch(ctx, changed); err != nil {
+				config.errorHandler(err)
+			}
+		}()
+	}
+
+	return changed
+}
+
+// notify sends to the changed channel without blocking. If a notification is already pending, it's not needed to
+// send another one since the config will be reloaded anyway.
+func notify(changed chan<- struct{}) {
+	select {
+	case changed <- struct{}{}:
+	default:
+	}
+}
+
+// poll calls the state function in the given interval and notifies the changed channel whenever the returned
+// state differs from the previous one. If the interval is not positive, defaultPollInterval is used.
+func poll(ctx context.Context, interval time.Duration, changed chan<- struct{}, state func() string) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	last := state()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if current := state(); current != last {
+				last = current
+				notify(changed)
+			}
+		}
+	}
+}
+
+// deepCopy returns a copy of the given value that does not share any pointers, slices or maps with it.
+// Unexported fields are copied shallowly.
+func deepCopy(src reflect.Value) reflect.Value {
+	//nolint:exhaustive // all other kinds can be copied by value
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return src
+		}
+
+		dst := reflect.New(src.Type().Elem())
+		dst.Elem().Set(deepCopy(src.Elem()))
+
+		return dst
+	case reflect.Struct:
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(src)
+
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				dst.Field(i).Set(deepCopy(src.Field(i)))
+			}
+		}
+
+		return dst
+	case reflect.Slice:
+		if src.IsNil() {
+			return src
+		}
+
+		dst := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+
+		return dst
+	case reflect.Array:
+		dst := reflect.New(src.Type()).Elem()
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+
+		return dst
+	case reflect.Map:
+		if src.IsNil() {
+			return src
+		}
+
+		dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+
+		return dst
+	}
+
+	return src
+}
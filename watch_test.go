@@ -0,0 +1,200 @@
+package alligotor
+
+import (
+	"context"
+	"os"
+	"path"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("watch", func() {
+	type config struct {
+		Name  string
+		Level string
+		Sub   *struct{ Port int }
+	}
+
+	Describe("Collector.Watch", func() {
+		var (
+			ctx     context.Context
+			cancel  context.CancelFunc
+			source  *triggerSource
+			updates chan *config
+			done    chan error
+			cfg     *config
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			source = &triggerSource{values: pathSource{"Name": "first"}, trigger: make(chan struct{})}
+			updates = make(chan *config, 10)
+			done = make(chan error, 1)
+			cfg = &config{Level: "default", Sub: &struct{ Port int }{Port: 1}}
+		})
+		AfterEach(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		start := func(opts ...WatchOption) {
+			go func() {
+				done <- New(source).Watch(ctx, cfg, func(v interface{}) {
+					updates <- v.(*config)
+				}, opts...)
+			}()
+
+			Eventually(func() chan struct{} { return source.watching() }).ShouldNot(BeNil())
+		}
+
+		It("fills the config initially and calls the callback on changes", func() {
+			start()
+			Expect(cfg.Name).To(Equal("first"))
+
+			source.set(pathSource{"Name": "second", "Sub.Port": 2})
+			source.trigger <- struct{}{}
+
+			var updated *config
+			Eventually(updates).Should(Receive(&updated))
+			Expect(updated.Name).To(Equal("second"))
+			Expect(updated.Level).To(Equal("default"))
+			Expect(updated.Sub.Port).To(Equal(2))
+			Expect(cfg.Name).To(Equal("first"))
+			Expect(cfg.Sub.Port).To(Equal(1))
+		})
+		It("does not call the callback if nothing changed", func() {
+			start()
+
+			source.trigger <- struct{}{}
+			Consistently(updates, 100*time.Millisecond).ShouldNot(Receive())
+		})
+		It("keeps defaults for values that are not set anymore", func() {
+			start()
+
+			source.set(pathSource{"Level": "set"})
+			source.trigger <- struct{}{}
+			Eventually(updates).Should(Receive(HaveField("Level", "set")))
+
+			source.set(pathSource{})
+			source.trigger <- struct{}{}
+			Eventually(updates).Should(Receive(HaveField("Level", "default")))
+		})
+		It("reports errors to the error handler and keeps watching", func() {
+			errs := make(chan error, 1)
+			start(WithWatchErrorHandler(func(err error) { errs <- err }))
+
+			source.set(pathSource{"Name": 5})
+			source.trigger <- struct{}{}
+			Eventually(errs).Should(Receive(MatchError(ErrTypeMismatch)))
+
+			source.set(pathSource{"Name": "fixed"})
+			source.trigger <- struct{}{}
+			Eventually(updates).Should(Receive(HaveField("Name", "fixed")))
+		})
+		It("reloads on SIGHUP", func() {
+			start()
+
+			source.set(pathSource{"Name": "signal"})
+			Expect(syscall.Kill(os.Getpid(), syscall.SIGHUP)).To(Succeed())
+			Eventually(updates).Should(Receive(HaveField("Name", "signal")))
+		})
+	})
+	Describe("FilesSource.Watch", func() {
+		It("notifies about modified files", func() {
+			tempDir, err := os.MkdirTemp("", "tests*")
+			Expect(err).ShouldNot(HaveOccurred())
+			defer os.RemoveAll(tempDir)
+
+			filePath := path.Join(tempDir, "config.yml")
+			Expect(os.WriteFile(filePath, []byte("name: a"), 0600)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			changed := make(chan struct{}, 1)
+			s := NewFilesSource(path.Join(tempDir, "config.*")).WithPollInterval(10 * time.Millisecond)
+
+			go func() { _ = s.Watch(ctx, changed) }()
+
+			Consistently(changed, 50*time.Millisecond).ShouldNot(Receive())
+			Expect(os.WriteFile(filePath, []byte("name: changed"), 0600)).To(Succeed())
+			Eventually(changed).Should(Receive())
+		})
+	})
+	Describe("deepCopy", func() {
+		It("does not share pointers, slices or maps", func() {
+			original := &struct {
+				Ptr   *int
+				Slice []string
+				Map   map[string]string
+			}{Ptr: new(int), Slice: []string{"a"}, Map: map[string]string{"a": "a"}}
+
+			copied := deepCopy(reflect.ValueOf(original)).Interface().(*struct {
+				Ptr   *int
+				Slice []string
+				Map   map[string]string
+			})
+			Expect(copied).To(Equal(original))
+
+			*copied.Ptr = 1
+			copied.Slice[0] = "b"
+			copied.Map["a"] = "b"
+			Expect(*original.Ptr).To(Equal(0))
+			Expect(original.Slice).To(Equal([]string{"a"}))
+			Expect(original.Map).To(Equal(map[string]string{"a": "a"}))
+		})
+	})
+})
+
+// triggerSource is a ConfigSourceWatcher that notifies about changes whenever something is sent to trigger.
+type triggerSource struct {
+	mu      sync.Mutex
+	values  pathSource
+	trigger chan struct{}
+	changed chan<- struct{}
+}
+
+func (s *triggerSource) Read(field *Field) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values.Read(field)
+}
+
+func (s *triggerSource) set(values pathSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = values
+}
+
+func (s *triggerSource) watching() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.changed == nil {
+		return nil
+	}
+
+	return s.trigger
+}
+
+func (s *triggerSource) Watch(ctx context.Context, changed chan<- struct{}) error {
+	s.mu.Lock()
+	s.changed = changed
+	s.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.trigger:
+			notify(changed)
+		}
+	}
+}